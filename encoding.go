@@ -0,0 +1,285 @@
+package timeseries
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MarshalJSON - Encode t as an array of [x, y] pairs, e.g. [[1,100],[2,50]]
+func (t Timeseries) MarshalJSON() ([]byte, error) {
+	if err := t.checkLength(); err != nil {
+		return nil, err
+	}
+
+	pairs := make([][2]float64, t.Len())
+	for i := range pairs {
+		pairs[i] = [2]float64{t.Xs[i], t.Ys[i]}
+	}
+
+	return json.Marshal(pairs)
+}
+
+// UnmarshalJSON - Decode an array of [x, y] pairs, as produced by MarshalJSON, into t.
+// As is the convention for json.Unmarshaler, null leaves t unchanged.
+func (t *Timeseries) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	// Decode through pointers so that a null x or y is an error, not a 0
+	var rows [][]*float64
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	ret := makeTimeseries(len(rows))
+	for i, row := range rows {
+		if len(row) != 2 {
+			return fmt.Errorf("timeseries: row %d has %d values; expected an [x, y] pair", i, len(row))
+		}
+
+		if row[0] == nil || row[1] == nil {
+			return fmt.Errorf("timeseries: row %d has a null value; expected an [x, y] pair", i)
+		}
+
+		ret.Xs[i], ret.Ys[i] = *row[0], *row[1]
+	}
+
+	*t = ret
+	return nil
+}
+
+// ReadCSV - Read a two-column x,y CSV from r into a new Timeseries.
+// A header row is skipped if the first row does not parse as two floats;
+// any later row that does not parse results in an error naming its record,
+// counting from 1 and skipping blank lines like encoding/csv does.
+func ReadCSV(r io.Reader) (Timeseries, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	var ret Timeseries
+	for record := 1; ; record++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return ret, nil
+		} else if err != nil {
+			return Timeseries{}, fmt.Errorf("timeseries: reading CSV: %w", err)
+		}
+
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err == nil {
+			var y float64
+			if y, err = strconv.ParseFloat(fields[1], 64); err == nil {
+				ret.Append(x, y)
+				continue
+			}
+		}
+
+		if record > 1 {
+			return Timeseries{}, fmt.Errorf("timeseries: reading CSV record %d: %w", record, err)
+		}
+	}
+}
+
+// WriteCSV - Write t to w as a two-column CSV with an x,y header row.
+// Floats are written at full precision, so ReadCSV recovers t exactly.
+func (t Timeseries) WriteCSV(w io.Writer) error {
+	if err := t.checkLength(); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"x", "y"}); err != nil {
+		return err
+	}
+
+	for i, x := range t.Xs {
+		record := []string{
+			strconv.FormatFloat(x, 'g', -1, 64),
+			strconv.FormatFloat(t.Ys[i], 'g', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// errMalformedGob is returned when decoding gob data not produced by GobEncode
+var errMalformedGob = errors.New("timeseries: malformed gob data")
+
+// GobEncode - Encode t as the number of points as a uvarint, followed by the
+// Xs and then the Ys as little-endian IEEE 754 doubles
+func (t Timeseries) GobEncode() ([]byte, error) {
+	if err := t.checkLength(); err != nil {
+		return nil, err
+	}
+
+	n := t.Len()
+	buf := make([]byte, binary.MaxVarintLen64+16*n)
+	off := binary.PutUvarint(buf, uint64(n))
+
+	for _, xs := range [][]float64{t.Xs, t.Ys} {
+		for _, v := range xs {
+			binary.LittleEndian.PutUint64(buf[off:], math.Float64bits(v))
+			off += 8
+		}
+	}
+
+	return buf[:off], nil
+}
+
+// GobDecode - Decode data, as produced by GobEncode, into t
+func (t *Timeseries) GobDecode(data []byte) error {
+	n, off := binary.Uvarint(data)
+	if off <= 0 || n > uint64(len(data)-off)/16 || uint64(len(data)-off) != 16*n {
+		return errMalformedGob
+	}
+
+	ret := makeTimeseries(int(n))
+	for _, xs := range [][]float64{ret.Xs, ret.Ys} {
+		for i := range xs {
+			xs[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[off:]))
+			off += 8
+		}
+	}
+
+	*t = ret
+	return nil
+}
+
+// binaryMagic starts every series written by WriteBinary
+const binaryMagic = "TSER"
+
+// binaryVersion is the version of the format written by WriteBinary.  It must
+// be bumped whenever the layout following the version byte changes, and
+// ReadBinary rejects any version it does not know.
+const binaryVersion = 1
+
+// errMalformedBinary is returned when reading data not produced by WriteBinary
+var errMalformedBinary = errors.New("timeseries: malformed binary data")
+
+// WriteBinary - Write t to w in a compact binary format, laid out as
+//  - the magic bytes "TSER"
+//  - a version byte, currently 1
+//  - the number of points as a uvarint
+//  - the Xs, each as the zig-zag varint of the difference between the
+//    IEEE 754 bits of the X and those of the previous X, or of 0 for the first
+//  - the Ys as little-endian IEEE 754 doubles
+// As sorted and regularly spaced Xs have nearby bit patterns, the Xs
+// typically take far fewer than 8 bytes each, while every value, including
+// NaNs and infinities, is stored losslessly.
+func (t Timeseries) WriteBinary(w io.Writer) error {
+	if err := t.checkLength(); err != nil {
+		return err
+	}
+
+	n := t.Len()
+	buf := make([]byte, len(binaryMagic)+1+binary.MaxVarintLen64*(1+n)+8*n)
+	off := copy(buf, binaryMagic)
+	buf[off] = binaryVersion
+	off++
+	off += binary.PutUvarint(buf[off:], uint64(n))
+
+	var prev uint64
+	for _, x := range t.Xs {
+		bits := math.Float64bits(x)
+		off += binary.PutVarint(buf[off:], int64(bits-prev))
+		prev = bits
+	}
+
+	for _, y := range t.Ys {
+		binary.LittleEndian.PutUint64(buf[off:], math.Float64bits(y))
+		off += 8
+	}
+
+	_, err := w.Write(buf[:off])
+	return err
+}
+
+// ReadBinary - Read a series written by WriteBinary from r.
+// If r is not an io.ByteReader, ReadBinary may read past the end of the series.
+func ReadBinary(r io.Reader) (Timeseries, error) {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	ret, err := readBinary(br)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return Timeseries{}, fmt.Errorf("timeseries: reading binary: %w", err)
+	}
+
+	return ret, nil
+}
+
+// byteReader is satisfied by readers that readBinary can consume byte by byte
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// readBinary - Read the series in the format of WriteBinary from r
+func readBinary(r byteReader) (ret Timeseries, err error) {
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ret, err
+	}
+
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return ret, errMalformedBinary
+	}
+
+	if version := header[len(binaryMagic)]; version != binaryVersion {
+		return ret, fmt.Errorf("%w: unsupported version %d", errMalformedBinary, version)
+	}
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return ret, err
+	}
+
+	// Don't trust n for allocating, but grow the slices as points are read
+	const maxPrealloc = 1 << 16
+	if n < maxPrealloc {
+		ret = Timeseries{
+			Xs: make([]float64, 0, n),
+			Ys: make([]float64, 0, n),
+		}
+	}
+
+	var prev uint64
+	for i := uint64(0); i < n; i++ {
+		delta, err := binary.ReadVarint(r)
+		if err != nil {
+			return ret, err
+		}
+
+		prev += uint64(delta)
+		ret.Xs = append(ret.Xs, math.Float64frombits(prev))
+	}
+
+	var b [8]byte
+	for i := uint64(0); i < n; i++ {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return ret, err
+		}
+
+		ret.Ys = append(ret.Ys, math.Float64frombits(binary.LittleEndian.Uint64(b[:])))
+	}
+
+	return ret, nil
+}
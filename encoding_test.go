@@ -0,0 +1,290 @@
+package timeseries
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	if _, err := json.Marshal(mismatchedTimeseries); err == nil {
+		t.Fatalf("expected marshaling mismatchedTimeseries to fail")
+	}
+
+	if b, err := json.Marshal(emptyTimeseries); err != nil || string(b) != "[]" {
+		t.Fatalf("expected emptyTimeseries to marshal as []; instead got %s, %v", b, err)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3.5},
+		Ys: []float64{100, -50, 0.1},
+	}
+
+	b, err := json.Marshal(ts)
+	if err != nil {
+		t.Fatalf("expected marshaling ts to succeed; instead got %v", err)
+	}
+
+	if expected := "[[1,100],[2,-50],[3.5,0.1]]"; string(b) != expected {
+		t.Fatalf("expected ts to marshal as %s; instead got %s", expected, b)
+	}
+
+	var actual Timeseries
+	if err := json.Unmarshal(b, &actual); err != nil || !actual.Equal(ts) {
+		t.Fatalf("expected %s to unmarshal as %v; instead got %v, %v", b, ts, actual, err)
+	}
+
+	// Finite floats round-trip losslessly
+	precise := Timeseries{
+		Xs: []float64{1527000000.125, 1.0 / 3, -math.MaxFloat64},
+		Ys: []float64{math.SmallestNonzeroFloat64, 1e-300, math.Pi},
+	}
+	if b, err = json.Marshal(precise); err != nil {
+		t.Fatalf("expected marshaling %v to succeed; instead got %v", precise, err)
+	}
+	if err := json.Unmarshal(b, &actual); err != nil || !actual.Equal(precise) {
+		t.Fatalf("expected %s to unmarshal as %v; instead got %v, %v", b, precise, actual, err)
+	}
+
+	if err := json.Unmarshal([]byte("[]"), &actual); err != nil || !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected [] to unmarshal as an empty series; instead got %v, %v", actual, err)
+	}
+
+	actual = ts.Clone()
+	if err := json.Unmarshal([]byte("null"), &actual); err != nil || !actual.Equal(ts) {
+		t.Fatalf("expected null to leave the series unchanged; instead got %v, %v", actual, err)
+	}
+
+	var wrapper struct{ Series Timeseries }
+	wrapper.Series = ts.Clone()
+	if err := json.Unmarshal([]byte(`{"Series":null}`), &wrapper); err != nil || !wrapper.Series.Equal(ts) {
+		t.Fatalf("expected a null field to leave the series unchanged; instead got %v, %v", wrapper.Series, err)
+	}
+
+	for _, malformed := range []string{
+		"[[1,2],[3]]",
+		"[[1,2,3]]",
+		"[[]]",
+		`[[1,"2"]]`,
+		"[[1,null]]",
+		"[[null,2],[3,4]]",
+		"{}",
+	} {
+		if err := json.Unmarshal([]byte(malformed), &actual); err == nil {
+			t.Fatalf("expected unmarshaling %s to fail", malformed)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := mismatchedTimeseries.WriteCSV(&buf); err != ErrLengthMismatch {
+		t.Fatalf("expected writing mismatchedTimeseries to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1527000000, 1527000000.125, 1527000001},
+		Ys: []float64{1.0 / 3, -50, 1e-300},
+	}
+
+	buf.Reset()
+	if err := ts.WriteCSV(&buf); err != nil {
+		t.Fatalf("expected WriteCSV() to succeed; instead got %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "x,y\n") {
+		t.Fatalf("expected WriteCSV() to emit an x,y header; instead got %q", buf.String())
+	}
+
+	actual, err := ReadCSV(&buf)
+	if err != nil || !actual.Equal(ts) {
+		t.Fatalf("expected ReadCSV() to return %v; instead got %v, %v", ts, actual, err)
+	}
+
+	// Without a header
+	actual, err = ReadCSV(strings.NewReader("1,2\n3, 4\n"))
+	expected := Timeseries{
+		Xs: []float64{1, 3},
+		Ys: []float64{2, 4},
+	}
+	if err != nil || !actual.Equal(expected) {
+		t.Fatalf("expected ReadCSV() to return %v; instead got %v, %v", expected, actual, err)
+	}
+
+	if actual, err := ReadCSV(strings.NewReader("")); err != nil || !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected ReadCSV() of empty input to return an empty series; instead got %v, %v", actual, err)
+	}
+
+	// Blank lines are not records
+	_, err = ReadCSV(strings.NewReader("time,value\n\n1,2\n\n\n3,four\n"))
+	if err == nil || !strings.Contains(err.Error(), "record 3") {
+		t.Fatalf("expected ReadCSV() to fail on record 3; instead got %v", err)
+	}
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected ReadCSV() to wrap the parse error; instead got %v", err)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("1,2\n3\n")); err == nil {
+		t.Fatalf("expected ReadCSV() to fail on a single-column row")
+	}
+}
+
+func TestGob(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(mismatchedTimeseries); err == nil {
+		t.Fatalf("expected encoding mismatchedTimeseries to fail")
+	}
+
+	for _, ts := range []Timeseries{
+		emptyTimeseries,
+		{
+			Xs: []float64{1, 2, 3, 4},
+			Ys: []float64{100, math.Inf(-1), -0.5, 1e300},
+		},
+	} {
+		buf.Reset()
+		if err := gob.NewEncoder(&buf).Encode(ts); err != nil {
+			t.Fatalf("expected encoding %v to succeed; instead got %v", ts, err)
+		}
+
+		var actual Timeseries
+		if err := gob.NewDecoder(&buf).Decode(&actual); err != nil || !actual.Equal(ts) {
+			t.Fatalf("expected decoding to return %v; instead got %v, %v", ts, actual, err)
+		}
+	}
+
+	data, _ := Timeseries{Xs: []float64{1, 2}, Ys: []float64{3, 4}}.GobEncode()
+
+	previous := Timeseries{Xs: []float64{5}, Ys: []float64{6}}
+	for _, malformed := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append(data[:len(data):len(data)], 0),
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+	} {
+		actual := previous.Clone()
+		if err := actual.GobDecode(malformed); err != errMalformedGob {
+			t.Fatalf("expected decoding %v to return errMalformedGob; instead got %v", malformed, err)
+		}
+
+		if !actual.Equal(previous) {
+			t.Fatalf("expected a failed decode to leave the series untouched; instead got %v", actual)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	var buf bytes.Buffer
+	if err := mismatchedTimeseries.WriteBinary(&buf); err != ErrLengthMismatch {
+		t.Fatalf("expected writing mismatchedTimeseries to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	var regular Timeseries
+	for i := 0; i < 1000; i++ {
+		regular.Append(1527000000+float64(i)/4, math.Sin(float64(i)))
+	}
+
+	for _, ts := range []Timeseries{
+		emptyTimeseries,
+		regular,
+		{
+			Xs: []float64{1.6e18, 1.6e18 + 512, -3, 1e300, math.Inf(1)},
+			Ys: []float64{100, math.Inf(-1), -0.5, 1e-300, 0},
+		},
+	} {
+		buf.Reset()
+		if err := ts.WriteBinary(&buf); err != nil {
+			t.Fatalf("expected writing %v to succeed; instead got %v", ts, err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "TSER\x01") {
+			t.Fatalf("expected WriteBinary() to emit the magic and version; instead got %q", buf.Bytes()[:5])
+		}
+
+		actual, err := ReadBinary(&buf)
+		if err != nil || !actual.Equal(ts) {
+			t.Fatalf("expected ReadBinary() to return %v; instead got %v, %v", ts, actual, err)
+		}
+	}
+
+	// Regularly spaced Xs are delta-encoded in fewer than 8 bytes each
+	buf.Reset()
+	if err := regular.WriteBinary(&buf); err != nil {
+		t.Fatalf("expected writing regular to succeed; instead got %v", err)
+	}
+	if n, limit := buf.Len(), 13*regular.Len(); n > limit {
+		t.Fatalf("expected %d points to take at most %d bytes; instead got %d", regular.Len(), limit, n)
+	}
+
+	// Series can be read back to back
+	small := regular.Slice(0, 3)
+	buf.Reset()
+	small.WriteBinary(&buf)
+	small.WriteBinary(&buf)
+	for i := 0; i < 2; i++ {
+		if actual, err := ReadBinary(&buf); err != nil || !actual.Equal(small) {
+			t.Fatalf("expected ReadBinary() #%d to return %v; instead got %v, %v", i, small, actual, err)
+		}
+	}
+
+	buf.Reset()
+	small.WriteBinary(&buf)
+	data := buf.Bytes()
+
+	for _, malformed := range [][]byte{
+		[]byte("TSEX\x01\x00"),
+		[]byte("TSER\x02\x00"),
+	} {
+		if _, err := ReadBinary(bytes.NewReader(malformed)); !errors.Is(err, errMalformedBinary) {
+			t.Fatalf("expected reading %q to fail with errMalformedBinary; instead got %v", malformed, err)
+		}
+	}
+
+	for _, truncated := range [][]byte{
+		nil,
+		data[:3],
+		data[:5],
+		data[:len(data)-1],
+	} {
+		if _, err := ReadBinary(bytes.NewReader(truncated)); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected reading %q to fail with io.ErrUnexpectedEOF; instead got %v", truncated, err)
+		}
+	}
+}
+
+// plainTimeseries has the same fields as Timeseries, but uses the default gob encoding
+type plainTimeseries struct {
+	Xs []float64
+	Ys []float64
+}
+
+func benchmarkGob(b *testing.B, v func(Timeseries) interface{}) {
+	var ts Timeseries
+	for i := 0; i < 100000; i++ {
+		ts.Append(1527000000+float64(i)/10, math.Sin(float64(i)))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(v(ts)); err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(buf.Len()))
+	}
+}
+
+func BenchmarkGobEncode(b *testing.B) {
+	benchmarkGob(b, func(ts Timeseries) interface{} { return ts })
+}
+
+func BenchmarkGobEncodeDefault(b *testing.B) {
+	benchmarkGob(b, func(ts Timeseries) interface{} { return plainTimeseries(ts) })
+}
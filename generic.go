@@ -0,0 +1,95 @@
+//go:build go1.18
+// +build go1.18
+
+package timeseries
+
+import "sort"
+
+// ordered is satisfied by the types usable as Xs of a TimeseriesOf
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// float is satisfied by the types usable as Ys of a TimeseriesOf
+type float interface {
+	~float32 | ~float64
+}
+
+// TimeseriesOf is a Timeseries with Xs of type X and Ys of type Y, for when
+// the Xs do not fit a float64 exactly, as with int64 Unix nanoseconds past
+// 2^53.  It supports the core operations on a series; the same invariants as
+// for Timeseries apply.
+type TimeseriesOf[X ordered, Y float] struct {
+	Xs []X
+	Ys []Y
+}
+
+// Len - return the length of the timeseries.
+// If len(Xs) != len(Ys), Len() panics
+func (t TimeseriesOf[X, Y]) Len() int {
+	if n := len(t.Xs); n != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	} else {
+		return n
+	}
+}
+
+// Append - Append value @ time to the timeseries
+// Note that you might need a sort if you're inserting points out-of-order
+func (t *TimeseriesOf[X, Y]) Append(x X, y Y) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	t.Xs = append(t.Xs, x)
+	t.Ys = append(t.Ys, y)
+}
+
+// After - Return a shallow copy of the items in the time series having Xs >= x
+// The series must be sorted.
+func (t TimeseriesOf[X, Y]) After(x X) TimeseriesOf[X, Y] {
+	i := t.findPivot(x)
+
+	return TimeseriesOf[X, Y]{
+		Xs: t.Xs[i:],
+		Ys: t.Ys[i:],
+	}
+}
+
+// Before - Return a shallow copy of the items in the time series having Xs < x.
+// The series must be sorted.
+func (t TimeseriesOf[X, Y]) Before(x X) TimeseriesOf[X, Y] {
+	j := t.findPivot(x)
+
+	return TimeseriesOf[X, Y]{
+		Xs: t.Xs[:j],
+		Ys: t.Ys[:j],
+	}
+}
+
+// Between - Return a shallow copy of the items in the time series between [x1, x2)
+func (t TimeseriesOf[X, Y]) Between(x1, x2 X) TimeseriesOf[X, Y] {
+	return t.After(x1).Before(x2)
+}
+
+// Slice slices the series equivalently to t[start:end]
+func (t TimeseriesOf[X, Y]) Slice(start, end int) TimeseriesOf[X, Y] {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	return TimeseriesOf[X, Y]{
+		Xs: t.Xs[start:end],
+		Ys: t.Ys[start:end],
+	}
+}
+
+// findPivot - Binary search for the location of x in t and return its index,
+// where the index will put i at before <= x < after
+func (t TimeseriesOf[X, Y]) findPivot(x X) int {
+	return sort.Search(t.Len(), func(i int) bool {
+		return t.Xs[i] >= x
+	})
+}
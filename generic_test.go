@@ -0,0 +1,67 @@
+//go:build go1.18
+// +build go1.18
+
+package timeseries
+
+import "testing"
+
+func TestTimeseriesOf(t *testing.T) {
+	mismatched := TimeseriesOf[int64, float64]{
+		Xs: []int64{1, 2},
+		Ys: []float64{3},
+	}
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatched.Len() })
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatched.Append(3, 4) })
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatched.After(1) })
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatched.Before(1) })
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatched.Slice(0, 1) })
+
+	// Unix nanoseconds one apart, which float64 cannot tell apart
+	const base = int64(1527000000000000000)
+	if float64(base) != float64(base+1) {
+		t.Fatalf("expected %v and %v to collide as float64", base, base+1)
+	}
+
+	var ts TimeseriesOf[int64, float64]
+	for i := int64(0); i < 5; i++ {
+		ts.Append(base+i, float64(10*i))
+	}
+
+	if n := ts.Len(); n != 5 {
+		t.Fatalf("expected 5 items; instead got %v", n)
+	}
+
+	for i, x := range ts.Xs {
+		if x != base+int64(i) {
+			t.Fatalf("expected Xs[%d] = %v; instead got %v", i, base+int64(i), x)
+		}
+	}
+
+	after := ts.After(base + 3)
+	if len(after.Xs) != 2 || after.Xs[0] != base+3 || after.Ys[0] != 30 {
+		t.Fatalf("expected After(%v) to start at %v; instead got %v", base+3, base+3, after)
+	}
+
+	before := ts.Before(base + 1)
+	if len(before.Xs) != 1 || before.Xs[0] != base {
+		t.Fatalf("expected Before(%v) to hold only %v; instead got %v", base+1, base, before)
+	}
+
+	between := ts.Between(base+1, base+3)
+	if len(between.Xs) != 2 || between.Xs[0] != base+1 || between.Xs[1] != base+2 {
+		t.Fatalf("expected Between(%v, %v) to hold %v and %v; instead got %v", base+1, base+3, base+1, base+2, between)
+	}
+
+	if empty := ts.After(base + 5); empty.Len() != 0 {
+		t.Fatalf("expected After() past the last X to be empty; instead got %v", empty)
+	}
+
+	if all := ts.Before(base + 5); all.Len() != 5 {
+		t.Fatalf("expected Before() past the last X to hold every item; instead got %v", all)
+	}
+
+	slice := ts.Slice(1, 3)
+	if slice.Len() != 2 || slice.Xs[0] != base+1 || slice.Ys[1] != 20 {
+		t.Fatalf("expected Slice(1, 3) to hold the second and third items; instead got %v", slice)
+	}
+}
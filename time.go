@@ -0,0 +1,50 @@
+package timeseries
+
+import (
+	"math"
+	"time"
+)
+
+// The methods in this file take the Xs of a series to be Unix timestamps in
+// seconds, as returned by UnixSeconds.  Note that a float64 holds a
+// present-day timestamp to within about a quarter of a microsecond, so
+// sub-microsecond differences between times may be lost.
+
+// UnixSeconds - Return tm as the number of seconds elapsed since January 1,
+// 1970 UTC, including the fractional part
+func UnixSeconds(tm time.Time) float64 {
+	return float64(tm.Unix()) + float64(tm.Nanosecond())/1e9
+}
+
+// FromUnixSeconds - Return the local time corresponding to x seconds since
+// January 1, 1970 UTC; the inverse of UnixSeconds, up to its precision
+func FromUnixSeconds(x float64) time.Time {
+	sec, frac := math.Modf(x)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9)))
+}
+
+// AppendTime - Append value @ time to the timeseries, as by Append()
+func (t *Timeseries) AppendTime(at time.Time, y float64) {
+	t.Append(UnixSeconds(at), y)
+}
+
+// AfterTime - Return a shallow copy of the items in the time series at or
+// after at, as by After().
+// The series must be sorted.
+func (t Timeseries) AfterTime(at time.Time) Timeseries {
+	return t.After(UnixSeconds(at))
+}
+
+// BeforeTime - Return a shallow copy of the items in the time series before
+// at, as by Before().
+// The series must be sorted.
+func (t Timeseries) BeforeTime(at time.Time) Timeseries {
+	return t.Before(UnixSeconds(at))
+}
+
+// BetweenTime - Return a shallow copy of the items in the time series between
+// [from, to), as by Between().
+// The series must be sorted.
+func (t Timeseries) BetweenTime(from, to time.Time) Timeseries {
+	return t.Between(UnixSeconds(from), UnixSeconds(to))
+}
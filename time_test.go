@@ -0,0 +1,69 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnixSeconds(t *testing.T) {
+	for _, c := range []struct {
+		tm time.Time
+		x  float64
+	}{
+		{time.Unix(0, 0), 0},
+		{time.Unix(1527000000, 0), 1527000000},
+		{time.Unix(1527000000, 250000000), 1527000000.25},
+		{time.Unix(-1, 500000000), -0.5},
+	} {
+		if x := UnixSeconds(c.tm); x != c.x {
+			t.Fatalf("expected UnixSeconds(%v) = %v; instead got %v", c.tm, c.x, x)
+		}
+
+		if actual := FromUnixSeconds(c.x); !actual.Equal(c.tm) {
+			t.Fatalf("expected FromUnixSeconds(%v) = %v; instead got %v", c.x, c.tm, actual)
+		}
+	}
+
+	// Microseconds survive the round-trip
+	tm := time.Date(2018, 5, 22, 14, 40, 0, 123456000, time.UTC)
+	if d := FromUnixSeconds(UnixSeconds(tm)).Sub(tm); d < -time.Microsecond || d > time.Microsecond {
+		t.Fatalf("expected FromUnixSeconds() to recover %v to the microsecond; instead got %v off", tm, d)
+	}
+}
+
+func TestTimeMethods(t *testing.T) {
+	start := time.Date(2018, 5, 22, 14, 40, 0, 0, time.UTC)
+
+	var ts, expected Timeseries
+	for i := 0; i < 10; i++ {
+		at := start.Add(time.Duration(i) * 90 * time.Second)
+		ts.AppendTime(at, float64(i))
+		expected.Append(float64(at.Unix()), float64(i))
+	}
+
+	if !ts.Equal(expected) {
+		t.Fatalf("expected AppendTime() to append Unix seconds %v; instead got %v", expected, ts)
+	}
+
+	for _, offset := range []time.Duration{-time.Hour, 0, 90 * time.Second, 100 * time.Second, time.Hour} {
+		at := start.Add(offset)
+		x := UnixSeconds(at)
+
+		if actual, expected := ts.AfterTime(at), ts.After(x); !actual.Equal(expected) {
+			t.Fatalf("expected AfterTime(%v) to return %v; instead got %v", at, expected, actual)
+		}
+
+		if actual, expected := ts.BeforeTime(at), ts.Before(x); !actual.Equal(expected) {
+			t.Fatalf("expected BeforeTime(%v) to return %v; instead got %v", at, expected, actual)
+		}
+
+		to := at.Add(5 * time.Minute)
+		if actual, expected := ts.BetweenTime(at, to), ts.Between(x, UnixSeconds(to)); !actual.Equal(expected) {
+			t.Fatalf("expected BetweenTime(%v, %v) to return %v; instead got %v", at, to, expected, actual)
+		}
+	}
+
+	if actual := ts.BetweenTime(start.Add(90*time.Second), start.Add(270*time.Second)); actual.Len() != 2 || actual.Ys[0] != 1 {
+		t.Fatalf("expected BetweenTime() to return the second and third points; instead got %v", actual)
+	}
+}
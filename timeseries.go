@@ -4,17 +4,33 @@
 // You can manipulate them as you wish, but ensure two things:
 //
 // - Many of the methods in this library assume that the data is sorted.  If you
-//   do not insert in sorted order, ensure that you call Sort()
+//   do not insert in sorted order, ensure that you call Sort(), or check the
+//   order with IsSorted()
 //
 // - Ensure that Timeseries.Xs and Timeseries.Ys is always of equal length
 //   if you manipulate them without the accessors provided
 //
+// Most methods panic if the second constraint is violated.  Where a series
+// comes from an untrusted source, construct it with New() or check it with
+// Validate() to get an error instead.
+//
+// After(), Before(), Between() and Slice() return series sharing their backing
+// arrays with the receiver, just like slicing a Go slice does, so modifying the
+// items of one modifies the other.  Call Clone() on the result to get an
+// independent series.  Unless documented otherwise, the other methods
+// returning a series return a newly allocated one.
+//
 package timeseries
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 
+	"gonum.org/v1/gonum/interp"
 	"gonum.org/v1/gonum/stat"
 )
 
@@ -23,6 +39,71 @@ type Timeseries struct {
 	Ys []float64
 }
 
+var (
+	// ErrLengthMismatch is returned when the Xs and Ys of a series differ in length.
+	ErrLengthMismatch = errors.New("timeseries: Xs and Ys slice length mismatch")
+
+	// ErrEmpty is returned when accessing an item of an empty series.
+	ErrEmpty = errors.New("timeseries: empty timeseries")
+
+	// ErrOutOfBounds is returned when accessing an item past the bounds of a series.
+	ErrOutOfBounds = errors.New("timeseries: out of bounds")
+
+	// ErrNotFinite is wrapped by Validate when a series holds a NaN or an infinity.
+	ErrNotFinite = errors.New("timeseries: value is not finite")
+
+	// ErrNotSorted is wrapped by Validate when the Xs of a series are not sorted.
+	ErrNotSorted = errors.New("timeseries: Xs not sorted")
+)
+
+// New - Return a Timeseries over xs and ys, or ErrLengthMismatch if they are
+// not of equal length.  The slices are used as is, not copied.
+func New(xs, ys []float64) (Timeseries, error) {
+	t := Timeseries{Xs: xs, Ys: ys}
+	if err := t.checkLength(); err != nil {
+		return Timeseries{}, err
+	}
+
+	return t, nil
+}
+
+// Validate - Check that t satisfies the invariants the methods of this package
+// assume, returning nil if so.  Otherwise, return ErrLengthMismatch if the Xs
+// and Ys are not of equal length, or an error wrapping ErrNotFinite if any X
+// or Y is NaN or infinite, or ErrNotSorted if the Xs are not in ascending order
+func (t Timeseries) Validate() error {
+	if err := t.checkLength(); err != nil {
+		return err
+	}
+
+	for i, x := range t.Xs {
+		if y := t.Ys[i]; !isFinite(x) || !isFinite(y) {
+			return fmt.Errorf("%w: (%v, %v) at index %d", ErrNotFinite, x, y, i)
+		}
+
+		if i > 0 && x < t.Xs[i-1] {
+			return fmt.Errorf("%w: %v follows %v at index %d", ErrNotSorted, x, t.Xs[i-1], i)
+		}
+	}
+
+	return nil
+}
+
+// isFinite - Return true if v is neither NaN nor infinite
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// checkLength - Return ErrLengthMismatch if the Xs and Ys of t are not of
+// equal length, and nil otherwise
+func (t Timeseries) checkLength() error {
+	if len(t.Xs) != len(t.Ys) {
+		return ErrLengthMismatch
+	}
+
+	return nil
+}
+
 // First - Return the first x, y value of the timeseries.
 // If the timeseries contains no items, First() panics.
 func (t Timeseries) First() (x, y float64) {
@@ -35,6 +116,24 @@ func (t Timeseries) Last() (x, y float64) {
 	return t.At(t.Len() - 1)
 }
 
+// Range - Return the first and the last X of the timeseries, which are its
+// smallest and largest Xs if it is sorted; see Sort().
+// If the timeseries contains no items, Range() panics.
+func (t Timeseries) Range() (minX, maxX float64) {
+	minX, _ = t.First()
+	maxX, _ = t.Last()
+
+	return minX, maxX
+}
+
+// Span - Return the difference between the last and the first X of the
+// timeseries, which is the extent of its Xs if it is sorted; see Sort().
+// If the timeseries contains no items, Span() panics.
+func (t Timeseries) Span() float64 {
+	minX, maxX := t.Range()
+	return maxX - minX
+}
+
 // At - return the x, y pair at index i
 // If i does not represent a valid index, At panics
 func (t Timeseries) At(i int) (x, y float64) {
@@ -50,6 +149,26 @@ func (t Timeseries) At(i int) (x, y float64) {
 	return t.Xs[i], t.Ys[i]
 }
 
+// TryAt - return the x, y pair at index i
+// Unlike At, TryAt returns ErrLengthMismatch, ErrEmpty or ErrOutOfBounds
+// instead of panicking
+func (t Timeseries) TryAt(i int) (x, y float64, err error) {
+	if err := t.checkLength(); err != nil {
+		return 0, 0, err
+	}
+
+	n := t.Len()
+	if n == 0 {
+		return 0, 0, ErrEmpty
+	}
+
+	if i >= n || i < 0 {
+		return 0, 0, ErrOutOfBounds
+	}
+
+	return t.Xs[i], t.Ys[i], nil
+}
+
 // Equal - Return true if t and other represent the same time series
 func (t Timeseries) Equal(other Timeseries) bool {
 	if len(t.Xs) != len(t.Ys) || len(other.Xs) != len(other.Ys) {
@@ -69,6 +188,62 @@ func (t Timeseries) Equal(other Timeseries) bool {
 	return true
 }
 
+// EqualApprox - Return true if t and other have the same length and every
+// X and Y of t is within tol of the corresponding X and Y of other.
+// NaNs are never considered equal.
+func (t Timeseries) EqualApprox(other Timeseries, tol float64) bool {
+	if len(t.Xs) != len(t.Ys) || len(other.Xs) != len(other.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if t.Len() != other.Len() {
+		return false
+	}
+
+	within := func(a, b float64) bool {
+		return a == b || math.Abs(a-b) <= tol
+	}
+
+	for i := 0; i < t.Len(); i++ {
+		if !within(t.Xs[i], other.Xs[i]) || !within(t.Ys[i], other.Ys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// stringPoints is the number of points past which String truncates a series
+// to its first and last stringEdge points
+const (
+	stringPoints = 10
+	stringEdge   = 3
+)
+
+// String - Return a human-readable representation of t, such as
+// Timeseries(n=3, x=[1,2,3], y=[100,50,100]).  Series of more than 10 points
+// are truncated to their first and last 3 points, separated by an ellipsis.
+func (t Timeseries) String() string {
+	if len(t.Xs) != len(t.Ys) {
+		return fmt.Sprintf("Timeseries(len(Xs)=%d, len(Ys)=%d)", len(t.Xs), len(t.Ys))
+	}
+
+	format := func(vs []float64) string {
+		strs := make([]string, 0, stringPoints)
+		for i, v := range vs {
+			if len(vs) > stringPoints && i == stringEdge {
+				strs = append(strs, "...")
+			}
+			if len(vs) <= stringPoints || i < stringEdge || i >= len(vs)-stringEdge {
+				strs = append(strs, strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		}
+		return "[" + strings.Join(strs, ",") + "]"
+	}
+
+	return fmt.Sprintf("Timeseries(n=%d, x=%s, y=%s)", t.Len(), format(t.Xs), format(t.Ys))
+}
+
 // After - Return a shallow copy of the items in the time series having Xs >= x
 // The series must be sorted.
 func (t Timeseries) After(x float64) Timeseries {
@@ -113,164 +288,2109 @@ func (t Timeseries) Between(x1, x2 float64) Timeseries {
 	return t.After(x1).Before(x2)
 }
 
-// findPivot - Binary search for the location of x in t and return its index,
-// where the index will put i at before <= x < after
-func (t Timeseries) findPivot(x float64) int {
-	findAfter := func(i int) bool {
-		return t.Xs[i] >= x
+// InterpolateAt returns the Y at x, linearly interpolated between the two
+// points surrounding x.  If x is outside the range of Xs in the series,
+// InterpolateAt returns NaN; see InterpolateAtClamped() for an alternative.
+// The series must be sorted.  If the timeseries contains no items,
+// InterpolateAt panics.
+func (t Timeseries) InterpolateAt(x float64) float64 {
+	if t.Len() == 0 {
+		panic("timeseries: empty timeseries")
 	}
 
-	return sort.Search(t.Len(), findAfter)
+	if !(x >= t.Xs[0] && x <= t.Xs[t.Len()-1]) {
+		return math.NaN()
+	}
+
+	return t.interpolate(x)
 }
 
-// Append - Append value @ time to the timeseries
-// Note that you might need a sort if you're inserting points out-of-order
-func (t *Timeseries) Append(x float64, y float64) {
-	if len(t.Xs) != len(t.Ys) {
-		panic("timeseries: Xs and Ys slice length mismatch")
+// InterpolateAtClamped is like InterpolateAt(), but extrapolates flat outside
+// the range of Xs in the series, returning the first or the last Y.
+// The series must be sorted.  If the timeseries contains no items,
+// InterpolateAtClamped panics.
+func (t Timeseries) InterpolateAtClamped(x float64) float64 {
+	n := t.Len()
+	if n == 0 {
+		panic("timeseries: empty timeseries")
 	}
 
-	t.Xs = append(t.Xs, x)
-	t.Ys = append(t.Ys, y)
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case x <= t.Xs[0]:
+		return t.Ys[0]
+	case x >= t.Xs[n-1]:
+		return t.Ys[n-1]
+	}
+
+	return t.interpolate(x)
 }
 
-// Difference the timeseries N, returning a new series of length len(N)-1
-func (t Timeseries) Difference() (ret Timeseries) {
-	if len(t.Xs) != len(t.Ys) {
-		panic("timeseries: Xs and Ys slice length mismatch")
+// Resample returns a new series of n points at start, start+step, ...,
+// with each Y linearly interpolated from t as by ResampleAt().
+// The series must be sorted.  If the timeseries contains no items or
+// step <= 0, Resample panics.
+func (t Timeseries) Resample(start, step float64, n int) Timeseries {
+	if !(step > 0) {
+		panic("timeseries: invalid step")
 	}
 
-	if t.Len() < 2 {
-		// We must have at least two elements to difference
-		return ret
+	if n < 0 {
+		n = 0
 	}
 
-	ret = makeTimeseries(t.Len() - 1)
-	for i := 0; i < ret.Len(); i++ {
-		ret.Ys[i] = t.Ys[i+1] - t.Ys[i]
-		ret.Xs[i] = t.Xs[i+1]
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = start + float64(i)*step
 	}
 
-	return ret
+	return t.ResampleAt(xs)
 }
 
-// SimpleLinearRegression performs a simple linear regression of the series
-// computing the best fit line
-//  y = alpha + beta*x
-// such that rmse is minimized
-func (t Timeseries) LinearRegression() (alpha, beta, rmse float64) {
-	return t.WeightedLinearRegression(nil)
+// ResampleAt returns a new series with the given Xs, with each Y linearly
+// interpolated from t as by InterpolateAt().  Points falling outside the
+// range of Xs in t are NaN.
+// The series must be sorted.  If the timeseries contains no items,
+// ResampleAt panics.
+func (t Timeseries) ResampleAt(xs []float64) Timeseries {
+	return t.resample(xs, t.InterpolateAt)
 }
 
-// WeightedLinearRegression performs a weighted linear regression of the
-// series computing the best fit line
-//  y = alpha + beta*x
-// such that rmse is minimized
-func (t Timeseries) WeightedLinearRegression(weights []float64) (alpha, beta, rmse float64) {
-	if len(t.Xs) != len(t.Ys) {
-		panic("timeseries: Xs and Ys slice length mismatch")
+// ResampleAtClamped is like ResampleAt(), but points falling outside the
+// range of Xs in t are clamped to the first or the last Y as by
+// InterpolateAtClamped().
+// The series must be sorted.  If the timeseries contains no items,
+// ResampleAtClamped panics.
+func (t Timeseries) ResampleAtClamped(xs []float64) Timeseries {
+	return t.resample(xs, t.InterpolateAtClamped)
+}
+
+// resample - Return a new series with the given Xs, and Ys computed by interpolate
+func (t Timeseries) resample(xs []float64, interpolate func(float64) float64) Timeseries {
+	if t.Len() == 0 {
+		panic("timeseries: empty timeseries")
 	}
 
-	if weights != nil && len(t.Xs) != len(weights) {
-		panic("timeseries: Xs, Ys and weights slice length mismatch")
+	ret := makeTimeseries(len(xs))
+	copy(ret.Xs, xs)
+	for i, x := range xs {
+		ret.Ys[i] = interpolate(x)
 	}
 
-	alpha, beta = stat.LinearRegression(t.Xs, t.Ys, weights, false)
-	rmse = math.Sqrt(meanSquaredError(t.Xs, t.Ys, weights, alpha, beta))
+	return ret
+}
 
-	return alpha, beta, rmse
+// SplineResample fits a natural cubic spline through the points of t and
+// returns a new series with the given Xs, and Ys evaluated on the spline.
+// Points falling outside the range of Xs in t are clamped to the first or the
+// last Y.  The Xs of t must be strictly increasing.
+// If the timeseries contains fewer than three items, SplineResample panics.
+func (t Timeseries) SplineResample(xs []float64) Timeseries {
+	if t.Len() < 3 {
+		panic("timeseries: too few points for a spline")
+	}
+
+	for i := 1; i < t.Len(); i++ {
+		if !(t.Xs[i] > t.Xs[i-1]) {
+			panic("timeseries: Xs not strictly increasing")
+		}
+	}
+
+	var spline interp.NaturalCubic
+	if err := spline.Fit(t.Xs, t.Ys); err != nil {
+		panic(err)
+	}
+
+	ret := makeTimeseries(len(xs))
+	copy(ret.Xs, xs)
+	for i, x := range xs {
+		ret.Ys[i] = spline.Predict(x)
+	}
+
+	return ret
 }
 
-// MeanSquaredError returns the mean squared error defined as
-//  MSE = \sum_i w[i] * (y[i] - alpha + beta*x[i])^2 / (sum_i w_i)
-// for the line
-//  y = alpha + beta*x
-// and the data in x and y with the given weights.
-//
-// The lengths of x and y must be equal. If weights is nil then all of the
-// weights are 1. If weights is not nil, then len(x) must equal len(weights).
-//
-// TODO: Submit a PR to gonum
-func meanSquaredError(x, y, weights []float64, alpha, beta float64) (mse float64) {
-	if len(x) != len(y) {
-		panic("stat: slice length mismatch")
+// Merge aligns t and other on the sorted union of their Xs, returning both
+// series with a point at every X in the union.  Where a series has no point
+// at a given X, its Y is fill; no interpolation is done.
+// Both series must be sorted.
+func (t Timeseries) Merge(other Timeseries, fill float64) (Timeseries, Timeseries) {
+	if len(t.Xs) != len(t.Ys) || len(other.Xs) != len(other.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
 	}
-	if weights != nil && len(weights) != len(x) {
-		panic("stat: slice length mismatch")
+
+	n, m := t.Len(), other.Len()
+	a := Timeseries{
+		Xs: make([]float64, 0, n+m),
+		Ys: make([]float64, 0, n+m),
+	}
+	b := Timeseries{
+		Xs: make([]float64, 0, n+m),
+		Ys: make([]float64, 0, n+m),
 	}
 
-	sumWeights := 0.0
-	w := 1.0
-	for i, xi := range x {
-		if weights != nil {
-			w = weights[i]
+	for i, j := 0, 0; i < n || j < m; {
+		switch {
+		case j == m || i < n && t.Xs[i] < other.Xs[j]:
+			a.Append(t.Xs[i], t.Ys[i])
+			b.Append(t.Xs[i], fill)
+			i++
+		case i == n || other.Xs[j] < t.Xs[i]:
+			a.Append(other.Xs[j], fill)
+			b.Append(other.Xs[j], other.Ys[j])
+			j++
+		default:
+			a.Append(t.Xs[i], t.Ys[i])
+			b.Append(other.Xs[j], other.Ys[j])
+			i++
+			j++
 		}
-
-		yi := y[i]
-		fi := alpha + beta*xi
-		d := fi - yi
-		mse += w * d * d
-		sumWeights += w
 	}
 
-	return mse / sumWeights
+	return a, b
 }
 
-func makeTimeseries(length int) Timeseries {
-	return Timeseries{
-		Xs: make([]float64, length),
-		Ys: make([]float64, length),
+// FillMethod selects how FillGaps and FillLargeGaps impute the Y of a missing
+// point.
+type FillMethod int
+
+const (
+	// FillLinear interpolates linearly between the surrounding points.
+	FillLinear FillMethod = iota
+
+	// FillPrevious carries the Y of the preceding point forward.
+	FillPrevious
+
+	// FillZero sets the Y to zero.
+	FillZero
+)
+
+// FillGaps returns a new series on the regular grid First X, First X + step,
+// ..., up to the last X of t, followed by the last point of t if it does not
+// fall on the grid.  Grid points at which t has a point keep its Y, while the
+// Ys of the others are imputed according to method.  Other points of t are
+// only used for imputation; see FillLargeGaps() to keep every point of t.
+// A grid point within a billionth of a step of an X of t is taken to be that
+// X, absorbing rounding errors in First X + k*step.
+// The series must be sorted.  If the timeseries contains no items, step <= 0
+// or method is unknown, FillGaps panics.
+func (t Timeseries) FillGaps(step float64, method FillMethod) Timeseries {
+	if t.Len() == 0 {
+		panic("timeseries: empty timeseries")
+	}
+
+	if !(step > 0) {
+		panic("timeseries: invalid step")
+	}
+
+	checkFillMethod(method)
+
+	first, _ := t.First()
+	last, lastY := t.Last()
+
+	n := int(math.Floor((last-first)/step+fillTolerance)) + 1
+	ret := makeTimeseries(n)
+	for k := range ret.Xs {
+		x := math.Min(first+float64(k)*step, last)
+
+		// Snap the grid X to a real point within the tolerance of it
+		i := t.findPivot(x)
+		switch {
+		case t.Xs[i]-x <= fillTolerance*step:
+			ret.Xs[k], ret.Ys[k] = t.Xs[i], t.Ys[i]
+		case i > 0 && x-t.Xs[i-1] <= fillTolerance*step:
+			ret.Xs[k], ret.Ys[k] = t.Xs[i-1], t.Ys[i-1]
+		default:
+			ret.Xs[k], ret.Ys[k] = x, t.impute(x, i, method)
+		}
+	}
+
+	if ret.Xs[n-1] != last {
+		ret.Append(last, lastY)
 	}
+
+	return ret
 }
 
-// MovingAverage returns a time series representing the window-sized moving average over t
-func (t Timeseries) MovingAverage(window int) (ret Timeseries) {
+// FillLargeGaps returns a new series holding every point of t, plus points
+// imputed according to method wherever two consecutive Xs are more than
+// maxStep apart.  Such a gap is filled at maxStep resolution from its start,
+// leaving no point within a billionth of maxStep of its end.
+// The series must be sorted.  If maxStep <= 0 or method is unknown,
+// FillLargeGaps panics.
+func (t Timeseries) FillLargeGaps(maxStep float64, method FillMethod) (ret Timeseries) {
 	if len(t.Xs) != len(t.Ys) {
 		panic("timeseries: Xs and Ys slice length mismatch")
 	}
 
-	if t.Len() < window {
-		// If the length of t is less than window, we cannot return anything useul
-		return Timeseries{}
+	if !(maxStep > 0) {
+		panic("timeseries: invalid step")
 	}
 
-	var movingSum float64
+	checkFillMethod(method)
 
-	// Run the average
 	for i, x := range t.Xs {
-		movingSum += t.Ys[i]
-		if i >= window {
-			// We have a full window; start removing old entries
-			movingSum -= t.Ys[i-window]
-		} else if i < window-1 {
-			// Still accumulating a window
-			continue
+		if i > 0 && x-t.Xs[i-1] > maxStep {
+			start := t.Xs[i-1]
+			for k := 1; ; k++ {
+				fill := start + float64(k)*maxStep
+				if x-fill <= fillTolerance*maxStep {
+					break
+				}
+				ret.Append(fill, t.impute(fill, i, method))
+			}
 		}
 
-		ret.Append(x, movingSum/float64(window))
+		ret.Append(x, t.Ys[i])
 	}
 
 	return ret
 }
 
-// Slice slices the Timeseries equivalently to t[start:end]
-func (t Timeseries) Slice(start, end int) Timeseries {
-	if len(t.Xs) != len(t.Ys) {
-		panic("timeseries: Xs and Ys slice length mismatch")
+// fillTolerance is the fraction of a step within which the gap filling
+// methods consider two Xs equal
+const fillTolerance = 1e-9
+
+// checkFillMethod - Panic unless method is a known FillMethod
+func checkFillMethod(method FillMethod) {
+	if method < FillLinear || method > FillZero {
+		panic("timeseries: invalid fill method")
 	}
+}
 
-	return Timeseries{
-		Xs: t.Xs[start:end],
-		Ys: t.Ys[start:end],
+// impute - Return the Y at x, which must lie strictly between the Xs of the
+// points at i-1 and i of t, as imputed by method
+func (t Timeseries) impute(x float64, i int, method FillMethod) float64 {
+	switch method {
+	case FillLinear:
+		x0, y0 := t.Xs[i-1], t.Ys[i-1]
+		x1, y1 := t.Xs[i], t.Ys[i]
+		return y0 + (x-x0)*(y1-y0)/(x1-x0)
+	case FillPrevious:
+		return t.Ys[i-1]
 	}
+
+	return 0
 }
 
-func (t Timeseries) Sort() {
+// interpolate - Linearly interpolate the Y at x, where x must be within the
+// range of Xs in t
+func (t Timeseries) interpolate(x float64) float64 {
+	i := t.findPivot(x)
+	if t.Xs[i] == x {
+		return t.Ys[i]
+	}
+
+	x0, y0 := t.Xs[i-1], t.Ys[i-1]
+	x1, y1 := t.Xs[i], t.Ys[i]
+
+	return y0 + (x-x0)*(y1-y0)/(x1-x0)
+}
+
+// findPivot - Binary search for the location of x in t and return its index,
+// where the index will put i at before <= x < after
+func (t Timeseries) findPivot(x float64) int {
+	findAfter := func(i int) bool {
+		return t.Xs[i] >= x
+	}
+
+	return sort.Search(t.Len(), findAfter)
+}
+
+// Append - Append value @ time to the timeseries
+// Note that you might need a sort if you're inserting points out-of-order
+func (t *Timeseries) Append(x float64, y float64) {
 	if len(t.Xs) != len(t.Ys) {
 		panic("timeseries: Xs and Ys slice length mismatch")
 	}
 
-	sort.Sort(t)
+	t.Xs = append(t.Xs, x)
+	t.Ys = append(t.Ys, y)
+}
+
+// AppendSeries - Append all points of other to the timeseries
+// Note that you might need a sort if other is not newer than t
+func (t *Timeseries) AppendSeries(other Timeseries) {
+	t.AppendMany(other.Xs, other.Ys)
+}
+
+// AppendMany - Append the points xs[i] @ ys[i] to the timeseries, growing it
+// at most once.  If xs and ys differ in length, AppendMany panics.
+// Note that you might need a sort if the points are not newer than t
+func (t *Timeseries) AppendMany(xs, ys []float64) {
+	if len(t.Xs) != len(t.Ys) || len(xs) != len(ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	t.Xs = append(t.Xs, xs...)
+	t.Ys = append(t.Ys, ys...)
+}
+
+// InsertSorted - Insert value @ time into the sorted timeseries, keeping it
+// sorted.  A point sharing its X with existing points is inserted after them.
+// The series must be sorted.
+func (t *Timeseries) InsertSorted(x float64, y float64) {
+	i := t.findPivot(x)
+	for i < len(t.Xs) && t.Xs[i] == x {
+		i++
+	}
+
+	t.Xs = append(t.Xs, 0)
+	copy(t.Xs[i+1:], t.Xs[i:])
+	t.Xs[i] = x
+
+	t.Ys = append(t.Ys, 0)
+	copy(t.Ys[i+1:], t.Ys[i:])
+	t.Ys[i] = y
+}
+
+// DeleteBetween - Remove the items in the time series between [x1, x2) in
+// place, shifting the later items down.
+// The series must be sorted.
+func (t *Timeseries) DeleteBetween(x1, x2 float64) {
+	i, j := t.findPivot(x1), t.findPivot(x2)
+	if j <= i {
+		return
+	}
+
+	t.Xs = append(t.Xs[:i], t.Xs[j:]...)
+	t.Ys = append(t.Ys[:i], t.Ys[j:]...)
+}
+
+// RemoveAt - Remove the item at index i from the timeseries in place,
+// shifting the later items down.
+// If i does not represent a valid index, RemoveAt panics
+func (t *Timeseries) RemoveAt(i int) {
+	if i < 0 || i >= t.Len() {
+		panic("timeseries: out of bounds")
+	}
+
+	t.Xs = append(t.Xs[:i], t.Xs[i+1:]...)
+	t.Ys = append(t.Ys[:i], t.Ys[i+1:]...)
+}
+
+// RemoveIndices - Remove the items at the given indices from the timeseries
+// in place, in a single pass.  indices may be in any order and hold
+// duplicates, and is left untouched.
+// If any index is invalid, RemoveIndices panics and leaves t untouched
+func (t *Timeseries) RemoveIndices(indices []int) {
+	n := t.Len()
+
+	sorted := append([]int(nil), indices...)
+	sort.Ints(sorted)
+	if len(sorted) > 0 && (sorted[0] < 0 || sorted[len(sorted)-1] >= n) {
+		panic("timeseries: out of bounds")
+	}
+
+	kept := 0
+	for i := 0; i < n; i++ {
+		if len(sorted) > 0 && sorted[0] == i {
+			for len(sorted) > 0 && sorted[0] == i {
+				sorted = sorted[1:]
+			}
+			continue
+		}
+
+		t.Xs[kept], t.Ys[kept] = t.Xs[i], t.Ys[i]
+		kept++
+	}
+
+	t.Xs, t.Ys = t.Xs[:kept], t.Ys[:kept]
+}
+
+// Difference the timeseries N, returning a new series of length len(N)-1
+func (t Timeseries) Difference() Timeseries {
+	return t.DiffN(1)
+}
+
+// DiffN differences the timeseries N at the given lag, returning a new series
+// of length len(N)-lag, where the Y at Xs[i+lag] is Ys[i+lag]-Ys[i].
+// If lag < 1, DiffN panics.
+func (t Timeseries) DiffN(lag int) (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if lag < 1 {
+		panic("timeseries: invalid lag")
+	}
+
+	if t.Len() <= lag {
+		// We must have more than lag elements to difference
+		return ret
+	}
+
+	ret = makeTimeseries(t.Len() - lag)
+	for i := 0; i < ret.Len(); i++ {
+		ret.Ys[i] = t.Ys[i+lag] - t.Ys[i]
+		ret.Xs[i] = t.Xs[i+lag]
+	}
+
+	return ret
+}
+
+// Derivative returns the discrete slope of the series, taking the spacing of
+// the Xs into account.  Like Difference(), the result has length Len()-1 and
+// the slope between points i and i+1 is placed at Xs[i+1].
+// If two consecutive Xs are equal, the slope is ±Inf, or NaN if their Ys are
+// equal as well.
+func (t Timeseries) Derivative() (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if t.Len() < 2 {
+		return ret
+	}
+
+	ret = makeTimeseries(t.Len() - 1)
+	for i := 0; i < ret.Len(); i++ {
+		ret.Ys[i] = (t.Ys[i+1] - t.Ys[i]) / (t.Xs[i+1] - t.Xs[i])
+		ret.Xs[i] = t.Xs[i+1]
+	}
+
+	return ret
+}
+
+// PercentChange returns the relative change between consecutive Ys in the
+// series, (y[i+1]-y[i])/y[i], so that a doubling is a change of 1.  Like
+// Difference(), the result has length Len()-1 and the change between points
+// i and i+1 is placed at Xs[i+1].
+// A change from a Y of zero is ±Inf, or NaN if the next Y is zero as well.
+func (t Timeseries) PercentChange() (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if t.Len() < 2 {
+		return ret
+	}
+
+	ret = makeTimeseries(t.Len() - 1)
+	for i := 0; i < ret.Len(); i++ {
+		ret.Ys[i] = (t.Ys[i+1] - t.Ys[i]) / t.Ys[i]
+		ret.Xs[i] = t.Xs[i+1]
+	}
+
+	return ret
+}
+
+// Shift returns a new series where the Ys of t are moved n positions relative
+// to the Xs, pairing Xs[i] with Ys[i-n].  A positive n lags the Ys, a negative
+// n leads them, and the points left without a pair are dropped, so the result
+// has length Len()-|n|.
+// If |n| >= Len(), Shift returns an empty series.
+func (t Timeseries) Shift(n int) (ret Timeseries) {
+	m := t.Len()
+	if n >= m || -n >= m {
+		return ret
+	}
+
+	var xs, ys []float64
+	if n >= 0 {
+		xs, ys = t.Xs[n:], t.Ys[:m-n]
+	} else {
+		xs, ys = t.Xs[:m+n], t.Ys[-n:]
+	}
+
+	ret = makeTimeseries(len(xs))
+	copy(ret.Xs, xs)
+	copy(ret.Ys, ys)
+
+	return ret
+}
+
+// CumulativeSum returns a new series of the same length as t, where the Y at
+// index i is the sum of the Ys of t up to and including i.
+func (t Timeseries) CumulativeSum() Timeseries {
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+
+	var sum float64
+	for i, y := range t.Ys {
+		sum += y
+		ret.Ys[i] = sum
+	}
+
+	return ret
+}
+
+// Integrate returns the definite integral of Y over X using the trapezoidal
+// rule, taking the actual spacing of the Xs into account.
+// If t has fewer than two items, Integrate returns 0.
+func (t Timeseries) Integrate() (area float64) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for i := 1; i < t.Len(); i++ {
+		area += (t.Xs[i] - t.Xs[i-1]) * (t.Ys[i] + t.Ys[i-1]) / 2
+	}
+
+	return area
+}
+
+// CumulativeIntegral returns the running trapezoidal integral of Y over X.
+// Like Difference(), the result has length Len()-1, and the area accumulated
+// up to the interval between points i and i+1 is placed at Xs[i+1].
+func (t Timeseries) CumulativeIntegral() (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if t.Len() < 2 {
+		return ret
+	}
+
+	ret = makeTimeseries(t.Len() - 1)
+
+	var area float64
+	for i := 0; i < ret.Len(); i++ {
+		area += (t.Xs[i+1] - t.Xs[i]) * (t.Ys[i] + t.Ys[i+1]) / 2
+		ret.Xs[i] = t.Xs[i+1]
+		ret.Ys[i] = area
+	}
+
+	return ret
+}
+
+// SimpleLinearRegression performs a simple linear regression of the series
+// computing the best fit line
+//  y = alpha + beta*x
+// such that rmse is minimized
+func (t Timeseries) LinearRegression() (alpha, beta, rmse float64) {
+	return t.WeightedLinearRegression(nil)
+}
+
+// WeightedLinearRegression performs a weighted linear regression of the
+// series computing the best fit line
+//  y = alpha + beta*x
+// such that rmse is minimized
+func (t Timeseries) WeightedLinearRegression(weights []float64) (alpha, beta, rmse float64) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if weights != nil && len(t.Xs) != len(weights) {
+		panic("timeseries: Xs, Ys and weights slice length mismatch")
+	}
+
+	alpha, beta = stat.LinearRegression(t.Xs, t.Ys, weights, false)
+	rmse = math.Sqrt(meanSquaredError(t.Xs, t.Ys, weights, alpha, beta))
+
+	return alpha, beta, rmse
+}
+
+// Forecast fits a line to the series as LinearRegression() does and
+// returns n points extrapolated along it at lastX+step, lastX+2*step, ...
+// where lastX is the X of the last point in the series.
+// If n <= 0, Forecast returns an empty series.
+// If the timeseries contains no items, Forecast panics.
+func (t Timeseries) Forecast(n int, step float64) (ret Timeseries) {
+	lastX, _ := t.Last()
+	if n <= 0 {
+		return ret
+	}
+
+	alpha, beta, _ := t.LinearRegression()
+
+	ret = makeTimeseries(n)
+	for i := range ret.Xs {
+		x := lastX + float64(i+1)*step
+		ret.Xs[i] = x
+		ret.Ys[i] = alpha + beta*x
+	}
+
+	return ret
+}
+
+// HoltWinters fits an additive Holt-Winters (triple exponential smoothing)
+// model with the smoothing factors alpha for the level, beta for the trend and
+// gamma for the seasonal component, which repeats every period points.
+// It returns horizon forecasted points past the last X of the series, spaced
+// at the mean X spacing of the series.
+// The trend is initialized from the first two seasons, so the series must
+// hold at least 2*period points.  If any smoothing factor is outside [0, 1],
+// period < 2 or Len() < 2*period, HoltWinters panics.
+func (t Timeseries) HoltWinters(alpha, beta, gamma float64, period, horizon int) (ret Timeseries) {
+	for _, factor := range []float64{alpha, beta, gamma} {
+		if !(factor >= 0 && factor <= 1) {
+			panic("timeseries: smoothing factor out of range")
+		}
+	}
+
+	if period < 2 {
+		panic("timeseries: invalid period")
+	}
+
+	n := t.Len()
+	if n < 2*period {
+		panic("timeseries: too few points for the period")
+	}
+
+	if horizon <= 0 {
+		return ret
+	}
+
+	// The trend starts at the mean change between the first two seasons.
+	// The level starts at the mean of the first season, carried back to
+	// just before the first point along the trend, and the seasonal
+	// components at the mean detrended deviation of each complete season
+	// from its mean
+	var trend float64
+	for i := 0; i < period; i++ {
+		trend += (t.Ys[period+i] - t.Ys[i]) / float64(period*period)
+	}
+
+	level := t.Slice(0, period).Mean() - trend*float64(period+1)/2
+
+	seasons := n / period
+	seasonals := make([]float64, period)
+	for j := 0; j < seasons; j++ {
+		season := t.Slice(j*period, (j+1)*period)
+		mean := season.Mean()
+		for i, y := range season.Ys {
+			offset := trend * (float64(i) - float64(period-1)/2)
+			seasonals[i] += (y - mean - offset) / float64(seasons)
+		}
+	}
+
+	for i, y := range t.Ys {
+		s := seasonals[i%period]
+		lastLevel := level
+		level = alpha*(y-s) + (1-alpha)*(level+trend)
+		trend = beta*(level-lastLevel) + (1-beta)*trend
+		seasonals[i%period] = gamma*(y-level) + (1-gamma)*s
+	}
+
+	firstX, _ := t.First()
+	lastX, _ := t.Last()
+	step := (lastX - firstX) / float64(n-1)
+
+	ret = makeTimeseries(horizon)
+	for m := range ret.Xs {
+		ret.Xs[m] = lastX + float64(m+1)*step
+		ret.Ys[m] = level + float64(m+1)*trend + seasonals[(n+m)%period]
+	}
+
+	return ret
+}
+
+// MeanSquaredError returns the mean squared error defined as
+//  MSE = \sum_i w[i] * (y[i] - alpha + beta*x[i])^2 / (sum_i w_i)
+// for the line
+//  y = alpha + beta*x
+// and the data in x and y with the given weights.
+//
+// The lengths of x and y must be equal. If weights is nil then all of the
+// weights are 1. If weights is not nil, then len(x) must equal len(weights).
+//
+// TODO: Submit a PR to gonum
+func meanSquaredError(x, y, weights []float64, alpha, beta float64) (mse float64) {
+	if len(x) != len(y) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(x) {
+		panic("stat: slice length mismatch")
+	}
+
+	sumWeights := 0.0
+	w := 1.0
+	for i, xi := range x {
+		if weights != nil {
+			w = weights[i]
+		}
+
+		yi := y[i]
+		fi := alpha + beta*xi
+		d := fi - yi
+		mse += w * d * d
+		sumWeights += w
+	}
+
+	return mse / sumWeights
+}
+
+func makeTimeseries(length int) Timeseries {
+	return Timeseries{
+		Xs: make([]float64, length),
+		Ys: make([]float64, length),
+	}
+}
+
+// MovingAverage returns a time series representing the window-sized moving average over t.
+// The result has Len()-window+1 items, each one placed at the X of the last point in its window.
+// If window < 1 or window > Len(), MovingAverage panics.
+func (t Timeseries) MovingAverage(window int) (ret Timeseries) {
+	t.checkWindow(window)
+
+	var movingSum float64
+
+	// Run the average
+	for i, x := range t.Xs {
+		movingSum += t.Ys[i]
+		if i >= window {
+			// We have a full window; start removing old entries
+			movingSum -= t.Ys[i-window]
+		} else if i < window-1 {
+			// Still accumulating a window
+			continue
+		}
+
+		ret.Append(x, movingSum/float64(window))
+	}
+
+	return ret
+}
+
+// WeightedMovingAverage returns a time series representing the weighted moving
+// average over t, using a window of len(weights).  Within each window the
+// weights are applied oldest-first, so the last weight applies to the most
+// recent point, and the result is normalized by the sum of the weights.
+// Output alignment matches MovingAverage().
+// WeightedMovingAverage panics if weights is empty, contains a negative weight,
+// sums to zero or is longer than t.
+func (t Timeseries) WeightedMovingAverage(weights []float64) Timeseries {
+	if len(weights) == 0 {
+		panic("timeseries: empty weights")
+	}
+
+	var sumWeights float64
+	for _, w := range weights {
+		if w < 0 {
+			panic("timeseries: negative weight")
+		}
+
+		sumWeights += w
+	}
+
+	if sumWeights == 0 {
+		panic("timeseries: weights sum to zero")
+	}
+
+	window := len(weights)
+	t.checkWindow(window)
+
+	ret := makeTimeseries(t.Len() - window + 1)
+	for i := range ret.Xs {
+		var sum float64
+		for k, w := range weights {
+			sum += w * t.Ys[i+k]
+		}
+
+		ret.Xs[i] = t.Xs[i+window-1]
+		ret.Ys[i] = sum / sumWeights
+	}
+
+	return ret
+}
+
+// ExponentialMovingAverage returns a time series of the same length as t,
+// representing the exponential moving average over t with smoothing factor
+// alpha:
+//  ema[0] = y[0]
+//  ema[i] = alpha*y[i] + (1-alpha)*ema[i-1]
+// If alpha is not in (0, 1], ExponentialMovingAverage panics.
+func (t Timeseries) ExponentialMovingAverage(alpha float64) Timeseries {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("timeseries: alpha out of range")
+	}
+
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+
+	for i, y := range t.Ys {
+		if i == 0 {
+			ret.Ys[i] = y
+		} else {
+			ret.Ys[i] = alpha*y + (1-alpha)*ret.Ys[i-1]
+		}
+	}
+
+	return ret
+}
+
+// ExponentialMovingAverageSpan returns the exponential moving average over t
+// with the smoothing factor derived from span as alpha = 2/(span+1).
+// If span < 1, ExponentialMovingAverageSpan panics.
+func (t Timeseries) ExponentialMovingAverageSpan(span int) Timeseries {
+	if span < 1 {
+		panic("timeseries: invalid span")
+	}
+
+	return t.ExponentialMovingAverage(2 / (float64(span) + 1))
+}
+
+// GaussianSmooth returns a new series with the Ys of t convolved with a
+// discrete Gaussian kernel of standard deviation sigma, measured in points and
+// truncated at ±3 sigma.  The series keeps its length: near either end, the
+// kernel reaches into the Ys of t reflected about the first or the last point.
+// As the reflected Ys repeat every 2*(Len()-1) points, the kernel is further
+// truncated at 64 such periods, where it is practically flat anyway.
+// If sigma <= 0 or sigma is infinite, GaussianSmooth panics.
+func (t Timeseries) GaussianSmooth(sigma float64) Timeseries {
+	if !(sigma > 0) || math.IsInf(sigma, 1) {
+		panic("timeseries: invalid sigma")
+	}
+
+	n := t.Len()
+	ret := makeTimeseries(n)
+	copy(ret.Xs, t.Xs)
+	if n < 2 {
+		copy(ret.Ys, t.Ys)
+		return ret
+	}
+
+	// Map an index past either end of t back into it, reflecting about the
+	// first and the last point as often as needed
+	period := 2 * (n - 1)
+	reflect := func(j int) int {
+		if j %= period; j < 0 {
+			j += period
+		}
+		if j >= n {
+			j = period - j
+		}
+		return j
+	}
+
+	radius, capped := 64*period, true
+	if r := math.Ceil(3 * sigma); r < float64(radius) {
+		radius, capped = int(r), false
+	}
+
+	// A kernel longer than the period is folded onto it, with the weight at
+	// offset k applying to the Y at reflect(i+k)
+	folded := 2*radius+1 > period
+	offset, size := -radius, 2*radius+1
+	if folded {
+		offset, size = 0, period
+	}
+
+	kernel := make([]float64, size)
+	var sum float64
+	for d := -radius; d <= radius; d++ {
+		w := math.Exp(-float64(d) * float64(d) / (2 * sigma * sigma))
+		if capped && (d == -radius || d == radius) {
+			// The ends share a point of the period; count it once
+			w /= 2
+		}
+
+		k := d - offset
+		if folded {
+			if k %= period; k < 0 {
+				k += period
+			}
+		}
+
+		kernel[k] += w
+		sum += w
+	}
+
+	for i := range ret.Ys {
+		var y float64
+		for k, w := range kernel {
+			y += w * t.Ys[reflect(i+offset+k)]
+		}
+		ret.Ys[i] = y / sum
+	}
+
+	return ret
+}
+
+// Clone returns a deep copy of t.
+// After(), Before(), Between() and Slice() return series sharing their
+// backing arrays with t, so modifying their items modifies t as well.
+// The series returned by Clone does not share memory with t.
+func (t Timeseries) Clone() Timeseries {
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+	copy(ret.Ys, t.Ys)
+
+	return ret
+}
+
+// Reverse returns a new series holding the points of t in reverse order.
+// Note that the reversal of a sorted series is no longer sorted, and that
+// many methods assume that it is: call Sort() on the result before using it
+// with After(), Before(), Between() and the like.
+func (t Timeseries) Reverse() Timeseries {
+	n := t.Len()
+	ret := makeTimeseries(n)
+	for i := range t.Xs {
+		ret.Xs[n-1-i], ret.Ys[n-1-i] = t.Xs[i], t.Ys[i]
+	}
+
+	return ret
+}
+
+// ReverseInPlace is like Reverse(), but reverses the points of t in place.
+func (t Timeseries) ReverseInPlace() {
+	for i, j := 0, t.Len()-1; i < j; i, j = i+1, j-1 {
+		t.Swap(i, j)
+	}
+}
+
+// RollingSum returns a time series of the sum of the Ys in each window-sized
+// sliding window over t.  Output alignment matches MovingAverage().
+// If window < 1 or window > Len(), RollingSum panics.
+func (t Timeseries) RollingSum(window int) Timeseries {
+	t.checkWindow(window)
+
+	ret := makeTimeseries(t.Len() - window + 1)
+
+	var movingSum float64
+	for i, y := range t.Ys {
+		movingSum += y
+		if i >= window {
+			movingSum -= t.Ys[i-window]
+		}
+
+		if j := i - window + 1; j >= 0 {
+			ret.Xs[j] = t.Xs[i]
+			ret.Ys[j] = movingSum
+		}
+	}
+
+	return ret
+}
+
+// RollingStdDev returns a time series of the population standard deviation
+// of the Ys in each window-sized sliding window over t.  Output alignment
+// matches MovingAverage().
+// If window < 1 or window > Len(), RollingStdDev panics.
+func (t Timeseries) RollingStdDev(window int) Timeseries {
+	return t.RollingApply(window, Timeseries.StdDev)
+}
+
+// RollingSampleStdDev is like RollingStdDev(), but returns the sample standard
+// deviation, that is, using N-1 in the denominator.
+// If window < 2 or window > Len(), RollingSampleStdDev panics.
+func (t Timeseries) RollingSampleStdDev(window int) Timeseries {
+	if window < 2 {
+		panic("timeseries: invalid window size")
+	}
+
+	return t.RollingApply(window, Timeseries.SampleStdDev)
+}
+
+// BollingerBands returns the window-sized MovingAverage() of t as middle,
+// along with the upper and lower bands lying k population standard
+// deviations of the same windows above and below it, as by RollingStdDev().
+// The three series have the same Xs, but do not share memory.
+// If window < 1, window > Len() or k < 0, BollingerBands panics.
+func (t Timeseries) BollingerBands(window int, k float64) (middle, upper, lower Timeseries) {
+	if !(k >= 0) {
+		panic("timeseries: invalid band width")
+	}
+
+	middle = t.MovingAverage(window)
+	sd := t.RollingStdDev(window)
+
+	upper, lower = middle.Clone(), middle.Clone()
+	for i, y := range middle.Ys {
+		upper.Ys[i] = y + k*sd.Ys[i]
+		lower.Ys[i] = y - k*sd.Ys[i]
+	}
+
+	return middle, upper, lower
+}
+
+// RollingMax returns a time series of the largest Y in each window-sized
+// sliding window over t, skipping NaNs.  Output alignment matches
+// MovingAverage().
+// If window < 1 or window > Len(), RollingMax panics.
+func (t Timeseries) RollingMax(window int) Timeseries {
+	return t.rolling(window, Max)
+}
+
+// RollingMin returns a time series of the smallest Y in each window-sized
+// sliding window over t, skipping NaNs.  Output alignment matches
+// MovingAverage().
+// If window < 1 or window > Len(), RollingMin panics.
+func (t Timeseries) RollingMin(window int) Timeseries {
+	return t.rolling(window, Min)
+}
+
+// RollingApply returns a time series of fn applied to each window-sized
+// sliding window over t, with output alignment matching MovingAverage().
+// Each window is passed to fn as a view sharing memory with t, so fn must not
+// modify it.
+// If window < 1 or window > Len(), RollingApply panics.
+func (t Timeseries) RollingApply(window int, fn func(sub Timeseries) float64) Timeseries {
+	t.checkWindow(window)
+
+	ret := makeTimeseries(t.Len() - window + 1)
+	for i := range ret.Xs {
+		ret.Xs[i] = t.Xs[i+window-1]
+		ret.Ys[i] = fn(t.Slice(i, i+window))
+	}
+
+	return ret
+}
+
+// rolling - Return a time series of agg applied to the Ys of each
+// window-sized sliding window over t
+func (t Timeseries) rolling(window int, agg func([]float64) float64) Timeseries {
+	return t.RollingApply(window, func(sub Timeseries) float64 {
+		return agg(sub.Ys)
+	})
+}
+
+// checkWindow - Panic unless window is a valid window size for t
+func (t Timeseries) checkWindow(window int) {
+	if window < 1 || window > t.Len() {
+		panic("timeseries: invalid window size")
+	}
+}
+
+// Slice slices the Timeseries equivalently to t[start:end]
+func (t Timeseries) Slice(start, end int) Timeseries {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	return Timeseries{
+		Xs: t.Xs[start:end],
+		Ys: t.Ys[start:end],
+	}
+}
+
+// Sum returns the sum of the Ys in the series.
+// The sum of an empty series is 0.  As per IEEE 754, the sum of a series
+// holding a NaN Y is NaN; see SumSkipNaN() for an alternative.
+func (t Timeseries) Sum() (sum float64) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for _, y := range t.Ys {
+		sum += y
+	}
+
+	return sum
+}
+
+// SumSkipNaN is like Sum(), but skips NaN Ys.
+func (t Timeseries) SumSkipNaN() float64 {
+	return t.skipNaN().Sum()
+}
+
+// Mean returns the arithmetic mean of the Ys in the series.
+// The mean of an empty series is NaN.  As per IEEE 754, so is the mean of a
+// series holding a NaN Y; see MeanSkipNaN() for an alternative.
+func (t Timeseries) Mean() float64 {
+	n := t.Len()
+	if n == 0 {
+		return math.NaN()
+	}
+
+	return t.Sum() / float64(n)
+}
+
+// MeanSkipNaN is like Mean(), but skips NaN Ys.
+// If every Y is NaN, MeanSkipNaN returns NaN.
+func (t Timeseries) MeanSkipNaN() float64 {
+	return t.skipNaN().Mean()
+}
+
+// Min returns the x, y pair having the smallest Y in the series.
+// NaN Ys are skipped, and if several points share the smallest Y the first one
+// is returned.  If every Y is NaN, the first point is returned.
+// If the timeseries contains no items, Min() panics.
+func (t Timeseries) Min() (x, y float64) {
+	_, i := t.MinY()
+	return t.Xs[i], t.Ys[i]
+}
+
+// Max returns the x, y pair having the largest Y in the series.
+// NaN Ys are skipped, and if several points share the largest Y the first one
+// is returned.  If every Y is NaN, the first point is returned.
+// If the timeseries contains no items, Max() panics.
+func (t Timeseries) Max() (x, y float64) {
+	_, i := t.MaxY()
+	return t.Xs[i], t.Ys[i]
+}
+
+// MinMax returns the x, y pairs having the smallest and the largest Y in the
+// series in a single pass.  NaNs and ties are handled as in Min() and Max().
+// If the timeseries contains no items, MinMax() panics.
+func (t Timeseries) MinMax() (minX, minY, maxX, maxY float64) {
+	if t.Len() == 0 {
+		panic("timeseries: empty timeseries")
+	}
+
+	minI, maxI := -1, -1
+	for i, y := range t.Ys {
+		if math.IsNaN(y) {
+			continue
+		}
+
+		if minI < 0 || y < t.Ys[minI] {
+			minI = i
+		}
+		if maxI < 0 || y > t.Ys[maxI] {
+			maxI = i
+		}
+	}
+
+	if minI < 0 {
+		minI, maxI = 0, 0
+	}
+
+	return t.Xs[minI], t.Ys[minI], t.Xs[maxI], t.Ys[maxI]
+}
+
+// Variance returns the population variance of the Ys in the series.
+// The variance of an empty series is NaN.
+func (t Timeseries) Variance() float64 {
+	if t.Len() == 0 {
+		return math.NaN()
+	}
+
+	return stat.PopVariance(t.Ys, nil)
+}
+
+// StdDev returns the population standard deviation of the Ys in the series.
+// The standard deviation of an empty series is NaN.  As per IEEE 754, so is
+// the standard deviation of a series holding a NaN Y; see StdDevSkipNaN() for
+// an alternative.
+func (t Timeseries) StdDev() float64 {
+	return math.Sqrt(t.Variance())
+}
+
+// StdDevSkipNaN is like StdDev(), but skips NaN Ys.
+// If every Y is NaN, StdDevSkipNaN returns NaN.
+func (t Timeseries) StdDevSkipNaN() float64 {
+	return t.skipNaN().StdDev()
+}
+
+// skipNaN - Return the points of t whose Y is not NaN
+func (t Timeseries) skipNaN() Timeseries {
+	return t.Filter(func(x, y float64) bool {
+		return !math.IsNaN(y)
+	})
+}
+
+// SampleVariance returns the sample variance of the Ys in the series,
+// that is, using N-1 in the denominator.
+// The sample variance of a series with fewer than two items is NaN.
+func (t Timeseries) SampleVariance() float64 {
+	if t.Len() < 2 {
+		return math.NaN()
+	}
+
+	return stat.Variance(t.Ys, nil)
+}
+
+// SampleStdDev returns the sample standard deviation of the Ys in the series,
+// that is, using N-1 in the denominator.
+// The sample standard deviation of a series with fewer than two items is NaN.
+func (t Timeseries) SampleStdDev() float64 {
+	if t.Len() < 2 {
+		return math.NaN()
+	}
+
+	return stat.StdDev(t.Ys, nil)
+}
+
+// Summary holds descriptive statistics of the Ys of a series, as returned by
+// Describe().
+type Summary struct {
+	Count        int     // Number of points
+	Mean         float64 // As by Mean()
+	Min          float64 // As by MinY()
+	Max          float64 // As by MaxY()
+	SampleStdDev float64 // As by SampleStdDev()
+}
+
+// Describe returns the Summary of the Ys in the series, computed in a single
+// pass.  Statistics that are undefined for the series, such as the minimum of
+// an empty series or the sample standard deviation of a single point, are NaN.
+func (t Timeseries) Describe() Summary {
+	s := Summary{
+		Count:        t.Len(),
+		Mean:         math.NaN(),
+		Min:          math.NaN(),
+		Max:          math.NaN(),
+		SampleStdDev: math.NaN(),
+	}
+
+	// Welford's online algorithm for the variance.  The mean is taken from
+	// the plain sum instead, so that it matches Mean() exactly.
+	var sum, mean, m2 float64
+	for i, y := range t.Ys {
+		sum += y
+		delta := y - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (y - mean)
+
+		if y < s.Min || (math.IsNaN(s.Min) && !math.IsNaN(y)) {
+			s.Min = y
+		}
+		if y > s.Max || (math.IsNaN(s.Max) && !math.IsNaN(y)) {
+			s.Max = y
+		}
+	}
+
+	if s.Count > 0 {
+		s.Mean = sum / float64(s.Count)
+	}
+	if s.Count > 1 {
+		s.SampleStdDev = math.Sqrt(m2 / float64(s.Count-1))
+	}
+
+	return s
+}
+
+// MinY returns the smallest Y in the series along with its index.
+// NaN Ys are skipped, and if several points share the smallest Y the index of
+// the first one is returned.  If every Y is NaN, MinY returns NaN at index 0.
+// If the timeseries contains no items, MinY() panics.
+func (t Timeseries) MinY() (y float64, index int) {
+	i := t.ArgMin()
+	return t.Ys[i], i
+}
+
+// MaxY returns the largest Y in the series along with its index.
+// NaN Ys are skipped, and if several points share the largest Y the index of
+// the first one is returned.  If every Y is NaN, MaxY returns NaN at index 0.
+// If the timeseries contains no items, MaxY() panics.
+func (t Timeseries) MaxY() (y float64, index int) {
+	i := t.ArgMax()
+	return t.Ys[i], i
+}
+
+// ArgMin returns the index of the smallest Y in the series.
+// NaN Ys are skipped, and if several points share the smallest Y the index of
+// the first one is returned.  If every Y is NaN, ArgMin returns 0.
+// If the timeseries contains no items, ArgMin() panics.
+func (t Timeseries) ArgMin() int {
+	return t.extremum(func(a, b float64) bool { return a < b })
+}
+
+// ArgMax returns the index of the largest Y in the series.
+// NaN Ys are skipped, and if several points share the largest Y the index of
+// the first one is returned.  If every Y is NaN, ArgMax returns 0.
+// If the timeseries contains no items, ArgMax() panics.
+func (t Timeseries) ArgMax() int {
+	return t.extremum(func(a, b float64) bool { return a > b })
+}
+
+// extremum - Return the index of the first non-NaN Y that no other Y is better
+// than, or 0 if all Ys are NaN
+func (t Timeseries) extremum(better func(a, b float64) bool) int {
+	if t.Len() == 0 {
+		panic("timeseries: empty timeseries")
+	}
+
+	best := -1
+	for i, y := range t.Ys {
+		if math.IsNaN(y) {
+			continue
+		}
+
+		if best < 0 || better(y, t.Ys[best]) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		return 0
+	}
+
+	return best
+}
+
+// Median returns the median of the Ys in the series.
+// The median of an empty series, or of one holding a NaN Y, is NaN.
+func (t Timeseries) Median() float64 {
+	return t.Percentile(50)
+}
+
+// Percentile returns the p-th percentile of the Ys in the series, where p must
+// be in [0, 100].  See Quantile() for details.
+// The percentile of an empty series, or of one holding a NaN Y, is NaN.
+func (t Timeseries) Percentile(p float64) float64 {
+	if !(p >= 0 && p <= 100) {
+		panic("timeseries: percentile out of range")
+	}
+
+	return t.Quantile(p / 100)
+}
+
+// Quantile returns the p-quantile of the Ys in the series, where p must be in
+// [0, 1].  The p-quantile of n sorted Ys is taken at rank p*(n-1), counting
+// from 0, and linearly interpolated between the closest ranks when that rank
+// is fractional, as by R's default type 7 and NumPy's default.  This differs
+// from both kinds of gonum's stat.Quantile: for Ys [1 2 3 4], the 0.5-quantile
+// is 2.5, whereas stat.Empirical and stat.LinInterp give 2.
+// The Ys are sorted in a copy; t itself is not modified.
+// The quantile of an empty series, or of one holding a NaN Y, is NaN.
+func (t Timeseries) Quantile(p float64) float64 {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if !(p >= 0 && p <= 1) {
+		panic("timeseries: quantile out of range")
+	}
+
+	return quantile(t.Ys, p)
+}
+
+// quantile - Return the p-quantile of ys as Quantile() does, without modifying ys
+func quantile(ys []float64, p float64) float64 {
+	n := len(ys)
+	if n == 0 {
+		return math.NaN()
+	}
+
+	sorted := make([]float64, n)
+	copy(sorted, ys)
+	sort.Float64s(sorted)
+
+	// NaNs sort first, and would silently shift the ranks of the other Ys
+	if math.IsNaN(sorted[0]) {
+		return math.NaN()
+	}
+
+	rank := p * float64(n-1)
+	i := int(rank)
+	if i == n-1 {
+		return sorted[i]
+	}
+
+	return sorted[i] + (rank-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// Normalize returns a new series with the Ys of t linearly mapped into [0, 1],
+// so that the smallest Y becomes 0 and the largest becomes 1.
+// If all Ys are equal, every normalized Y is 0.
+func (t Timeseries) Normalize() Timeseries {
+	return t.Rescale(0, 1)
+}
+
+// Rescale returns a new series with the Ys of t linearly mapped into
+// [lo, hi], so that the smallest Y becomes lo and the largest becomes hi.
+// If all Ys are equal, every rescaled Y is lo.
+// If lo >= hi, Rescale panics.
+func (t Timeseries) Rescale(lo, hi float64) Timeseries {
+	if !(lo < hi) {
+		panic("timeseries: invalid range")
+	}
+
+	ret := makeTimeseries(t.Len())
+	if ret.Len() == 0 {
+		return ret
+	}
+
+	copy(ret.Xs, t.Xs)
+
+	_, min, _, max := t.MinMax()
+	for i, y := range t.Ys {
+		if max == min {
+			ret.Ys[i] = lo
+		} else {
+			ret.Ys[i] = lo + (y-min)/(max-min)*(hi-lo)
+		}
+	}
+
+	return ret
+}
+
+// Standardize returns a new series with the Ys of t converted to z-scores,
+// (y-mean)/stddev, using the sample standard deviation.
+// If the Ys have no spread, as in a constant or single-item series, every
+// standardized Y is 0.
+func (t Timeseries) Standardize() Timeseries {
+	n := t.Len()
+	ret := makeTimeseries(n)
+	copy(ret.Xs, t.Xs)
+
+	if n < 2 {
+		return ret
+	}
+
+	mean, sd := t.Mean(), t.SampleStdDev()
+	if sd == 0 {
+		return ret
+	}
+
+	for i, y := range t.Ys {
+		ret.Ys[i] = (y - mean) / sd
+	}
+
+	return ret
+}
+
+// Destandardize reverses Standardize(), returning a new series with the Ys of
+// t converted back from z-scores as y*std + mean.  To recover a series ts,
+// use ts.Mean() and ts.SampleStdDev() as mean and std.
+func (t Timeseries) Destandardize(mean, std float64) Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return y*std + mean
+	})
+}
+
+// Map returns a new series with the same Xs as t, where each Y is fn applied
+// to the corresponding point of t.
+func (t Timeseries) Map(fn func(x, y float64) float64) Timeseries {
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+
+	for i, y := range t.Ys {
+		ret.Ys[i] = fn(t.Xs[i], y)
+	}
+
+	return ret
+}
+
+// Filter returns a new series holding, in order, only the points of t for
+// which pred returns true.
+func (t Timeseries) Filter(pred func(x, y float64) bool) (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for i, x := range t.Xs {
+		if pred(x, t.Ys[i]) {
+			ret.Append(x, t.Ys[i])
+		}
+	}
+
+	return ret
+}
+
+// DropNaN returns a new series holding, in order, only the points of t whose
+// X and Y are both finite, that is, neither NaN nor infinite.
+func (t Timeseries) DropNaN() Timeseries {
+	return t.Filter(func(x, y float64) bool {
+		return isFinite(x) && isFinite(y)
+	})
+}
+
+// CountNaN returns the number of points of t whose X or Y is NaN or infinite,
+// that is, the number of points DropNaN() would remove.
+func (t Timeseries) CountNaN() (n int) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for i, x := range t.Xs {
+		if !isFinite(x) || !isFinite(t.Ys[i]) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ForwardFill returns a new series where each NaN Y of t is replaced by the
+// closest preceding Y that is not NaN.  Leading NaNs are left as they are.
+func (t Timeseries) ForwardFill() Timeseries {
+	ret := t.Clone()
+	for i := 1; i < ret.Len(); i++ {
+		if math.IsNaN(ret.Ys[i]) {
+			ret.Ys[i] = ret.Ys[i-1]
+		}
+	}
+
+	return ret
+}
+
+// BackFill returns a new series where each NaN Y of t is replaced by the
+// closest following Y that is not NaN.  Trailing NaNs are left as they are.
+func (t Timeseries) BackFill() Timeseries {
+	ret := t.Clone()
+	for i := ret.Len() - 2; i >= 0; i-- {
+		if math.IsNaN(ret.Ys[i]) {
+			ret.Ys[i] = ret.Ys[i+1]
+		}
+	}
+
+	return ret
+}
+
+// Scale returns a new series with the Ys of t multiplied by factor.
+func (t Timeseries) Scale(factor float64) Timeseries {
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+
+	for i, y := range t.Ys {
+		ret.Ys[i] = y * factor
+	}
+
+	return ret
+}
+
+// Offset returns a new series with delta added to the Ys of t.
+func (t Timeseries) Offset(delta float64) Timeseries {
+	ret := makeTimeseries(t.Len())
+	copy(ret.Xs, t.Xs)
+
+	for i, y := range t.Ys {
+		ret.Ys[i] = y + delta
+	}
+
+	return ret
+}
+
+// Clamp returns a new series with each Y of t constrained to [lo, hi].
+// NaN Ys are passed through unchanged.
+// If lo > hi, Clamp panics.
+func (t Timeseries) Clamp(lo, hi float64) Timeseries {
+	if lo > hi {
+		panic("timeseries: invalid range")
+	}
+
+	return t.Map(func(x, y float64) float64 {
+		return math.Max(lo, math.Min(hi, y))
+	})
+}
+
+// Abs returns a new series with the absolute value of each Y of t.
+func (t Timeseries) Abs() Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return math.Abs(y)
+	})
+}
+
+// Square returns a new series with the square of each Y of t.
+func (t Timeseries) Square() Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return y * y
+	})
+}
+
+// Sqrt returns a new series with the square root of each Y of t.
+// The square root of a negative Y is NaN.
+func (t Timeseries) Sqrt() Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return math.Sqrt(y)
+	})
+}
+
+// Log returns a new series with the natural logarithm of each Y of t.
+// The logarithm of zero is -Inf, and of a negative Y NaN.
+func (t Timeseries) Log() Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return math.Log(y)
+	})
+}
+
+// Exp returns a new series with e raised to each Y of t, the inverse of Log().
+func (t Timeseries) Exp() Timeseries {
+	return t.Map(func(x, y float64) float64 {
+		return math.Exp(y)
+	})
+}
+
+// Downsample groups the points of t into buckets of bucketWidth along the X
+// axis, where the point at x falls into bucket floor(x/bucketWidth), and
+// returns a series with one point per non-empty bucket.  Each point is placed
+// at the left edge of its bucket and its Y is agg applied to the Ys in the
+// bucket.  Mean, Min, Max and Last are ready-made aggregation functions.
+// agg must neither modify nor retain the slice passed to it.
+// The series must be sorted.  If bucketWidth <= 0, Downsample panics.
+func (t Timeseries) Downsample(bucketWidth float64, agg func([]float64) float64) (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if !(bucketWidth > 0) {
+		panic("timeseries: invalid bucket width")
+	}
+
+	for i := 0; i < t.Len(); {
+		bucket := math.Floor(t.Xs[i] / bucketWidth)
+
+		j := i + 1
+		for j < t.Len() && math.Floor(t.Xs[j]/bucketWidth) == bucket {
+			j++
+		}
+
+		ret.Append(bucket*bucketWidth, agg(t.Ys[i:j]))
+		i = j
+	}
+
+	return ret
+}
+
+// DedupeX collapses runs of points sharing the same X into a single point,
+// whose Y is agg applied to the Ys of the run.  Mean, Min, Max and Last are
+// ready-made aggregation functions, and if agg is nil, Mean is used.
+// agg must neither modify nor retain the slice passed to it.
+// The series must be sorted.
+func (t Timeseries) DedupeX(agg func([]float64) float64) (ret Timeseries) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if agg == nil {
+		agg = Mean
+	}
+
+	for i := 0; i < t.Len(); {
+		j := i + 1
+		for j < t.Len() && t.Xs[j] == t.Xs[i] {
+			j++
+		}
+
+		ret.Append(t.Xs[i], agg(t.Ys[i:j]))
+		i = j
+	}
+
+	return ret
+}
+
+// LTTB downsamples t to threshold points using the Largest-Triangle-Three-Buckets
+// algorithm, which preserves the visual shape of the series far better than
+// bucketed aggregation.  The first and last points are always retained.
+// If threshold < 3 or threshold >= Len(), t is returned as is.
+// The series must be sorted.
+func (t Timeseries) LTTB(threshold int) Timeseries {
+	n := t.Len()
+	if threshold < 3 || threshold >= n {
+		return t
+	}
+
+	ret := makeTimeseries(threshold)
+	ret.Xs[0], ret.Ys[0] = t.First()
+	ret.Xs[threshold-1], ret.Ys[threshold-1] = t.Last()
+
+	// The points between the first and the last are split into
+	// threshold-2 buckets, each contributing one point.  The bounds are
+	// computed in integers so that the last bucket ends exactly at n-1.
+	bucketStart := func(i int) int {
+		if start := i*(n-2)/(threshold-2) + 1; start < n {
+			return start
+		}
+		return n
+	}
+
+	a := 0
+	for i := 0; i < threshold-2; i++ {
+		// The third vertex of the triangle is the average of the next bucket
+		var avgX, avgY float64
+		next, nextEnd := bucketStart(i+1), bucketStart(i+2)
+		if i == threshold-3 {
+			next, nextEnd = n-1, n
+		}
+		for j := next; j < nextEnd; j++ {
+			avgX += t.Xs[j]
+			avgY += t.Ys[j]
+		}
+		avgX /= float64(nextEnd - next)
+		avgY /= float64(nextEnd - next)
+
+		// Pick the point in this bucket forming the largest triangle with
+		// the previously selected point and the average of the next bucket
+		ax, ay := t.Xs[a], t.Ys[a]
+		maxArea := -1.0
+		for j := bucketStart(i); j < bucketStart(i+1); j++ {
+			area := math.Abs((ax-avgX)*(t.Ys[j]-ay) - (ax-t.Xs[j])*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				a = j
+			}
+		}
+
+		ret.Xs[i+1], ret.Ys[i+1] = t.Xs[a], t.Ys[a]
+	}
+
+	return ret
+}
+
+// Mean returns the arithmetic mean of ys, or NaN if ys is empty.
+func Mean(ys []float64) float64 {
+	if len(ys) == 0 {
+		return math.NaN()
+	}
+
+	var sum float64
+	for _, y := range ys {
+		sum += y
+	}
+
+	return sum / float64(len(ys))
+}
+
+// Min returns the smallest non-NaN value in ys, or NaN if there is none.
+func Min(ys []float64) float64 {
+	min := math.NaN()
+	for _, y := range ys {
+		if y < min || math.IsNaN(min) {
+			min = y
+		}
+	}
+
+	return min
+}
+
+// Max returns the largest non-NaN value in ys, or NaN if there is none.
+func Max(ys []float64) float64 {
+	max := math.NaN()
+	for _, y := range ys {
+		if y > max || math.IsNaN(max) {
+			max = y
+		}
+	}
+
+	return max
+}
+
+// Last returns the last value in ys, or NaN if ys is empty.
+func Last(ys []float64) float64 {
+	if len(ys) == 0 {
+		return math.NaN()
+	}
+
+	return ys[len(ys)-1]
+}
+
+// ScaleInPlace multiplies the Ys of t by factor, modifying t.
+func (t Timeseries) ScaleInPlace(factor float64) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for i := range t.Ys {
+		t.Ys[i] *= factor
+	}
+}
+
+// OffsetInPlace adds delta to the Ys of t, modifying t.
+func (t Timeseries) OffsetInPlace(delta float64) {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	for i := range t.Ys {
+		t.Ys[i] += delta
+	}
+}
+
+// Add returns a new series with the Ys of other added to the Ys of t.
+// t and other must have exactly the same Xs, or Add panics.
+func (t Timeseries) Add(other Timeseries) Timeseries {
+	return t.combine(other, func(a, b float64) float64 { return a + b })
+}
+
+// Sub returns a new series with the Ys of other subtracted from the Ys of t.
+// t and other must have exactly the same Xs, or Sub panics.
+func (t Timeseries) Sub(other Timeseries) Timeseries {
+	return t.combine(other, func(a, b float64) float64 { return a - b })
+}
+
+// Mul returns a new series with the Ys of t multiplied by the Ys of other.
+// t and other must have exactly the same Xs, or Mul panics.
+func (t Timeseries) Mul(other Timeseries) Timeseries {
+	return t.combine(other, func(a, b float64) float64 { return a * b })
+}
+
+// Div returns a new series with the Ys of t divided by the Ys of other.
+// Division by zero yields ±Inf or NaN as per IEEE 754.
+// t and other must have exactly the same Xs, or Div panics.
+func (t Timeseries) Div(other Timeseries) Timeseries {
+	return t.combine(other, func(a, b float64) float64 { return a / b })
+}
+
+// combine - Return a new series with op applied to the Ys of t and other,
+// which must have exactly the same Xs
+func (t Timeseries) combine(other Timeseries, op func(a, b float64) float64) Timeseries {
+	if len(t.Xs) != len(t.Ys) || len(other.Xs) != len(other.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if t.Len() != other.Len() {
+		panic("timeseries: Xs of the series differ")
+	}
+
+	ret := makeTimeseries(t.Len())
+	for i, x := range t.Xs {
+		if x != other.Xs[i] {
+			panic("timeseries: Xs of the series differ")
+		}
+
+		ret.Xs[i] = x
+		ret.Ys[i] = op(t.Ys[i], other.Ys[i])
+	}
+
+	return ret
+}
+
+// AutoCorrelation returns the autocorrelation function of the Ys in the series
+// for lags 0 through maxLag, as a series with the lag on the X axis and the
+// correlation coefficient on the Y axis.  The coefficient at lag k is
+//  r[k] = \sum_i (y[i] - mean) * (y[i+k] - mean) / \sum_i (y[i] - mean)^2
+// so r[0] is always 1, unless the series is constant, in which case every
+// coefficient is NaN.  The Ys of the returned series hold the coefficients
+// alone, indexed by lag.
+// If maxLag < 0 or maxLag >= Len(), AutoCorrelation panics.
+func (t Timeseries) AutoCorrelation(maxLag int) Timeseries {
+	n := t.Len()
+	if maxLag < 0 || maxLag >= n {
+		panic("timeseries: invalid lag")
+	}
+
+	mean := t.Mean()
+
+	var variance float64
+	for _, y := range t.Ys {
+		variance += (y - mean) * (y - mean)
+	}
+
+	ret := makeTimeseries(maxLag + 1)
+	for k := range ret.Xs {
+		var covariance float64
+		for i := 0; i+k < n; i++ {
+			covariance += (t.Ys[i] - mean) * (t.Ys[i+k] - mean)
+		}
+
+		ret.Xs[k] = float64(k)
+		ret.Ys[k] = covariance / variance
+	}
+
+	return ret
+}
+
+// CrossCorrelation returns the normalized cross-correlation of the Ys of t and
+// other for lags -maxLag through maxLag, as a series with the lag on the X
+// axis and the correlation coefficient on the Y axis.  At lag k, t.Ys[i] is
+// paired with other.Ys[i+k]:
+//  r[k] = \sum_i (t[i] - mean(t)) * (other[i+k] - mean(other)) / (n * std(t) * std(other))
+// using population standard deviations, so a peak at a positive lag k means
+// that other lags behind t by k points.  If either series is constant,
+// every coefficient is NaN.  The Ys of the returned series hold the
+// coefficients alone, the one for lag k at index k+maxLag.
+// t and other must have the same length, and if maxLag < 0 or
+// maxLag >= Len(), CrossCorrelation panics.
+func (t Timeseries) CrossCorrelation(other Timeseries, maxLag int) Timeseries {
+	n := t.Len()
+	if other.Len() != n {
+		panic("timeseries: series lengths differ")
+	}
+
+	if maxLag < 0 || maxLag >= n {
+		panic("timeseries: invalid lag")
+	}
+
+	meanT, meanO := t.Mean(), other.Mean()
+	norm := float64(n) * t.StdDev() * other.StdDev()
+
+	ret := makeTimeseries(2*maxLag + 1)
+	for j := range ret.Xs {
+		k := j - maxLag
+
+		var covariance float64
+		for i := 0; i < n; i++ {
+			if i+k >= 0 && i+k < n {
+				covariance += (t.Ys[i] - meanT) * (other.Ys[i+k] - meanO)
+			}
+		}
+
+		ret.Xs[j] = float64(k)
+		ret.Ys[j] = covariance / norm
+	}
+
+	return ret
+}
+
+// Correlation returns the Pearson correlation coefficient of the Ys of t and
+// other, which must have the same length.  If either series is constant, the
+// correlation is NaN.
+func (t Timeseries) Correlation(other Timeseries) float64 {
+	if t.Len() != other.Len() {
+		panic("timeseries: series lengths differ")
+	}
+
+	return stat.Correlation(t.Ys, other.Ys, nil)
+}
+
+// Covariance returns the sample covariance of the Ys of t and other, that is,
+// using N-1 in the denominator.  The two series must have the same length.
+// The covariance of series with fewer than two items is NaN.
+func (t Timeseries) Covariance(other Timeseries) float64 {
+	if t.Len() != other.Len() {
+		panic("timeseries: series lengths differ")
+	}
+
+	if t.Len() < 2 {
+		return math.NaN()
+	}
+
+	return stat.Covariance(t.Ys, other.Ys, nil)
+}
+
+// Outliers returns the indices of the points whose Y deviates from the mean of
+// the Ys by more than threshold population standard deviations.  If there are
+// none, as in a constant series, Outliers returns an empty slice.
+func (t Timeseries) Outliers(threshold float64) []int {
+	isOutlier := t.zScoreOutlier(threshold)
+
+	outliers := []int{}
+	for i, y := range t.Ys {
+		if isOutlier(y) {
+			outliers = append(outliers, i)
+		}
+	}
+
+	return outliers
+}
+
+// RemoveOutliers returns a new series holding the points of t that are not
+// reported by Outliers(threshold).
+func (t Timeseries) RemoveOutliers(threshold float64) Timeseries {
+	isOutlier := t.zScoreOutlier(threshold)
+
+	return t.Filter(func(x, y float64) bool {
+		return !isOutlier(y)
+	})
+}
+
+// HampelFilter returns a new series where each Y deviating from the median of
+// the window-sized window centered on it by more than nSigmas times the
+// scaled median absolute deviation (MAD) of that window is replaced by the
+// window median.  The MAD is scaled by 1.4826, making it a consistent
+// estimator of the standard deviation for normally distributed data.
+// Points closer than window/2 to either end of the series, where the window
+// does not fit, are left unchanged, as are points whose window holds a NaN.
+// If window is not a positive odd number, HampelFilter panics.
+func (t Timeseries) HampelFilter(window int, nSigmas float64) Timeseries {
+	if window < 1 || window%2 == 0 {
+		panic("timeseries: invalid window size")
+	}
+
+	ret := t.Clone()
+
+	half := window / 2
+	deviations := make([]float64, window)
+	for i := half; i+half < t.Len(); i++ {
+		ys := t.Ys[i-half : i+half+1]
+		median := quantile(ys, 0.5)
+		for j, y := range ys {
+			deviations[j] = math.Abs(y - median)
+		}
+
+		mad := 1.4826 * quantile(deviations, 0.5)
+		if math.Abs(t.Ys[i]-median) > nSigmas*mad {
+			ret.Ys[i] = median
+		}
+	}
+
+	return ret
+}
+
+// ChangePoints returns the indices at which a two-sided CUSUM detector over
+// the changes between consecutive Ys signals a shift in the level of the
+// series.  The detector accumulates upward and downward changes separately,
+// subtracting drift at every step, and signals when either sum exceeds
+// threshold; both sums are then reset, so that later shifts are found too.
+// A lower threshold makes the detector more sensitive, while a higher drift
+// makes it ignore noise and slow trends, at the price of missing small shifts.
+// If there are no change points, ChangePoints returns an empty slice.
+// If threshold <= 0 or drift < 0, ChangePoints panics.
+func (t Timeseries) ChangePoints(threshold, drift float64) []int {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	if !(threshold > 0) {
+		panic("timeseries: threshold out of range")
+	}
+
+	if !(drift >= 0) {
+		panic("timeseries: negative drift")
+	}
+
+	changes := []int{}
+	var up, down float64
+	for i := 1; i < t.Len(); i++ {
+		d := t.Ys[i] - t.Ys[i-1]
+		up = math.Max(0, up+d-drift)
+		down = math.Max(0, down-d-drift)
+
+		if up > threshold || down > threshold {
+			changes = append(changes, i)
+			up, down = 0, 0
+		}
+	}
+
+	return changes
+}
+
+// zScoreOutlier - Return a function reporting whether y deviates from the mean
+// of t by more than threshold standard deviations
+func (t Timeseries) zScoreOutlier(threshold float64) func(y float64) bool {
+	mean, sd := t.Mean(), t.StdDev()
+
+	return func(y float64) bool {
+		return sd > 0 && math.Abs(y-mean) > threshold*sd
+	}
+}
+
+// IsSorted reports whether the Xs of the series are in non-decreasing order,
+// as many methods assume.
+func (t Timeseries) IsSorted() bool {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	return sort.Float64sAreSorted(t.Xs)
+}
+
+// Sort - Sort the points of t in place by ascending X.  The sort is not
+// stable, so points sharing an X may be reordered; see StableSort()
+func (t Timeseries) Sort() {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	sort.Sort(t)
+}
+
+// StableSort - Sort the points of t in place by ascending X, keeping points
+// sharing an X in their original order.  StableSort is slower than Sort(),
+// making O(n*log(n)*log(n)) swaps instead of O(n*log(n))
+func (t Timeseries) StableSort() {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	sort.Stable(t)
+}
+
+// SortByY - Sort the points of t in place by ascending Y, with NaNs first.
+// Each X stays paired with its Y, but the Xs are no longer sorted: call Sort()
+// before using t with any method that assumes they are, such as After()
+func (t Timeseries) SortByY() {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	sort.Sort(byY{t})
+}
+
+// SortByYDesc - Sort the points of t in place by descending Y, with NaNs last.
+// As with SortByY(), call Sort() before using t with methods that assume the
+// Xs are sorted
+func (t Timeseries) SortByYDesc() {
+	if len(t.Xs) != len(t.Ys) {
+		panic("timeseries: Xs and Ys slice length mismatch")
+	}
+
+	sort.Sort(sort.Reverse(byY{t}))
+}
+
+// byY orders the points of a Timeseries by Y, like sort.Float64Slice does
+type byY struct {
+	Timeseries
+}
+
+func (t byY) Less(i, j int) bool {
+	return t.Ys[i] < t.Ys[j] || (math.IsNaN(t.Ys[i]) && !math.IsNaN(t.Ys[j]))
 }
 
 // Len - return the lenght of the timeseries.
@@ -1,7 +1,10 @@
 package timeseries
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"sort"
 	"testing"
 )
 
@@ -13,6 +16,69 @@ var mismatchedTimeseries = Timeseries{
 var minX = math.Inf(-1)
 var maxX = math.Inf(1)
 
+func TestNew(t *testing.T) {
+	if _, err := New([]float64{1, 2}, []float64{3}); err != ErrLengthMismatch {
+		t.Fatalf("expected New() with mismatched slices to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	if _, err := New(nil, []float64{3}); err != ErrLengthMismatch {
+		t.Fatalf("expected New() with a nil Xs to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	if ts, err := New(nil, nil); err != nil || !ts.Equal(emptyTimeseries) {
+		t.Fatalf("expected New(nil, nil) to return an empty series; instead got %v, %v", ts, err)
+	}
+
+	// New only checks the lengths; see Validate for the other invariants
+	if _, err := New([]float64{2, 1}, []float64{math.NaN(), 4}); err != nil {
+		t.Fatalf("expected New() to accept an unsorted series holding NaNs; instead got %v", err)
+	}
+
+	xs, ys := []float64{1, 2}, []float64{3, 4}
+	ts, err := New(xs, ys)
+	if err != nil {
+		t.Fatalf("expected New() to succeed; instead got %v", err)
+	}
+
+	if expected := (Timeseries{Xs: xs, Ys: ys}); !ts.Equal(expected) {
+		t.Fatalf("expected New() to return %v; instead got %v", expected, ts)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := mismatchedTimeseries.Validate(); err != ErrLengthMismatch {
+		t.Fatalf("expected mismatchedTimeseries.Validate() to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	if err := emptyTimeseries.Validate(); err != nil {
+		t.Fatalf("expected emptyTimeseries.Validate() to return nil; instead got %v", err)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 2, 3},
+		Ys: []float64{3, 4, -5, 0},
+	}
+	if err := ts.Validate(); err != nil {
+		t.Fatalf("expected ts.Validate() to return nil; instead got %v", err)
+	}
+
+	cases := []struct {
+		ts       Timeseries
+		expected error
+	}{
+		{Timeseries{Xs: []float64{1, math.NaN()}, Ys: []float64{3, 4}}, ErrNotFinite},
+		{Timeseries{Xs: []float64{1, 2}, Ys: []float64{math.NaN(), 4}}, ErrNotFinite},
+		{Timeseries{Xs: []float64{minX, 2}, Ys: []float64{3, 4}}, ErrNotFinite},
+		{Timeseries{Xs: []float64{1, 2}, Ys: []float64{3, maxX}}, ErrNotFinite},
+		{Timeseries{Xs: []float64{1, 3, 2}, Ys: []float64{3, 4, 5}}, ErrNotSorted},
+	}
+	for _, c := range cases {
+		if err := c.ts.Validate(); !errors.Is(err, c.expected) {
+			t.Fatalf("expected %v.Validate() to return %v; instead got %v", c.ts, c.expected, err)
+		}
+	}
+}
+
 func TestAppend(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
 		mismatchedTimeseries.Append(0, 0)
@@ -32,6 +98,235 @@ func TestAppend(t *testing.T) {
 	}
 }
 
+func TestAppendSeries(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.AppendSeries(emptyTimeseries)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		var ts Timeseries
+		ts.AppendSeries(mismatchedTimeseries)
+	})
+
+	source := Timeseries{
+		Xs: []float64{1, 2},
+		Ys: []float64{123.4, 456.7},
+	}
+
+	var actual Timeseries
+	actual.AppendSeries(source)
+	if !actual.Equal(source) {
+		t.Fatalf("expected %v after appending to an empty series; instead got %v", source, actual)
+	}
+
+	actual.AppendSeries(Timeseries{Xs: []float64{3}, Ys: []float64{789}})
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{123.4, 456.7, 789},
+	}
+	if !actual.Equal(expected) {
+		t.Fatalf("expected %v after appending; instead got %v", expected, actual)
+	}
+}
+
+func TestAppendMany(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.AppendMany(nil, nil)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		var ts Timeseries
+		ts.AppendMany([]float64{1, 2}, []float64{3})
+	})
+
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{9, 8, 7, 6, 5}
+
+	var expected Timeseries
+	expected.Append(0, 10)
+	for i, x := range xs {
+		expected.Append(x, ys[i])
+	}
+
+	var actual Timeseries
+	actual.Append(0, 10)
+	actual.AppendMany(xs, ys)
+	if !actual.Equal(expected) {
+		t.Fatalf("expected AppendMany() to match repeated Append(): %v; instead got %v", expected, actual)
+	}
+
+	actual.AppendMany(nil, nil)
+	if !actual.Equal(expected) {
+		t.Fatalf("expected AppendMany() of no points to leave the series unchanged; instead got %v", actual)
+	}
+
+	xs[0] = 1337
+	if actual.Xs[1] != 1 {
+		t.Fatalf("expected AppendMany() to copy the points; instead got %v", actual)
+	}
+}
+
+func benchmarkAppend(b *testing.B, appendAll func(ts *Timeseries, xs, ys []float64)) {
+	xs := make([]float64, 100000)
+	ys := make([]float64, len(xs))
+	for i := range xs {
+		xs[i], ys[i] = float64(i), math.Sin(float64(i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var ts Timeseries
+		appendAll(&ts, xs, ys)
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	benchmarkAppend(b, func(ts *Timeseries, xs, ys []float64) {
+		for i, x := range xs {
+			ts.Append(x, ys[i])
+		}
+	})
+}
+
+func BenchmarkAppendMany(b *testing.B) {
+	benchmarkAppend(b, (*Timeseries).AppendMany)
+}
+
+func TestInsertSorted(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		ts := mismatchedTimeseries
+		ts.InsertSorted(1, 1)
+	})
+
+	var ts Timeseries
+	ts.InsertSorted(5, 50)
+	if expected := (Timeseries{Xs: []float64{5}, Ys: []float64{50}}); !ts.Equal(expected) {
+		t.Fatalf("expected inserting into an empty series to yield %v; instead got %v", expected, ts)
+	}
+
+	ts.InsertSorted(1, 10) // Front
+	ts.InsertSorted(9, 90) // End
+	ts.InsertSorted(3, 30) // Middle
+	ts.InsertSorted(5, 51) // After the equal X
+	ts.InsertSorted(5, 52)
+
+	expected := Timeseries{
+		Xs: []float64{1, 3, 5, 5, 5, 9},
+		Ys: []float64{10, 30, 50, 51, 52, 90},
+	}
+	if !ts.Equal(expected) || !ts.IsSorted() {
+		t.Fatalf("expected InsertSorted() to yield %v; instead got %v", expected, ts)
+	}
+}
+
+func TestDeleteBetween(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		ts := mismatchedTimeseries
+		ts.DeleteBetween(1, 2)
+	})
+
+	source := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{10, 20, 30, 40, 50},
+	}
+
+	cases := []struct {
+		x1, x2   float64
+		expected Timeseries
+	}{
+		{2, 4, Timeseries{Xs: []float64{1, 4, 5}, Ys: []float64{10, 40, 50}}},
+		{1.5, 3.5, Timeseries{Xs: []float64{1, 4, 5}, Ys: []float64{10, 40, 50}}},
+		{minX, maxX, Timeseries{Xs: []float64{}, Ys: []float64{}}},
+		{3, 3, source},
+		{4, 2, source},
+		{6, 10, source},
+	}
+	for _, c := range cases {
+		ts := source.Clone()
+		ts.DeleteBetween(c.x1, c.x2)
+		if !ts.Equal(c.expected) {
+			t.Fatalf("expected DeleteBetween(%v, %v) to yield %v; instead got %v", c.x1, c.x2, c.expected, ts)
+		}
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		ts := mismatchedTimeseries
+		ts.RemoveAt(0)
+	})
+
+	source := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{10, 20, 30, 40},
+	}
+
+	assertPanic(t, "timeseries: out of bounds", func() {
+		ts := source.Clone()
+		ts.RemoveAt(-1)
+	})
+
+	assertPanic(t, "timeseries: out of bounds", func() {
+		ts := source.Clone()
+		ts.RemoveAt(4)
+	})
+
+	ts := source.Clone()
+	ts.RemoveAt(0) // First
+	ts.RemoveAt(2) // Last
+	expected := Timeseries{
+		Xs: []float64{2, 3},
+		Ys: []float64{20, 30},
+	}
+	if !ts.Equal(expected) {
+		t.Fatalf("expected removing the first and the last point to yield %v; instead got %v", expected, ts)
+	}
+}
+
+func TestRemoveIndices(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		ts := mismatchedTimeseries
+		ts.RemoveIndices(nil)
+	})
+
+	source := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7},
+		Ys: []float64{10, 20, 30, 40, 50, 60, 70},
+	}
+
+	ts := source.Clone()
+	assertPanic(t, "timeseries: out of bounds", func() { ts.RemoveIndices([]int{1, 7}) })
+	assertPanic(t, "timeseries: out of bounds", func() { ts.RemoveIndices([]int{-1}) })
+	if !ts.Equal(source) {
+		t.Fatalf("expected a failed RemoveIndices() to leave the series untouched; instead got %v", ts)
+	}
+
+	cases := []struct {
+		indices  []int
+		expected Timeseries
+	}{
+		{nil, source},
+		{[]int{0}, Timeseries{Xs: []float64{2, 3, 4, 5, 6, 7}, Ys: []float64{20, 30, 40, 50, 60, 70}}},
+		{[]int{6}, Timeseries{Xs: []float64{1, 2, 3, 4, 5, 6}, Ys: []float64{10, 20, 30, 40, 50, 60}}},
+		{[]int{5, 1, 3, 1, 0}, Timeseries{Xs: []float64{3, 5, 7}, Ys: []float64{30, 50, 70}}},
+		{[]int{0, 1, 2, 3, 4, 5, 6}, Timeseries{Xs: []float64{}, Ys: []float64{}}},
+	}
+	for _, c := range cases {
+		ts := source.Clone()
+		ts.RemoveIndices(c.indices)
+		if !ts.Equal(c.expected) {
+			t.Fatalf("expected RemoveIndices(%v) to yield %v; instead got %v", c.indices, c.expected, ts)
+		}
+	}
+
+	indices := []int{3, 1, 2}
+	ts = source.Clone()
+	ts.RemoveIndices(indices)
+	if indices[0] != 3 || indices[1] != 1 || indices[2] != 2 {
+		t.Fatalf("expected RemoveIndices() to leave its argument untouched; instead got %v", indices)
+	}
+}
+
 func TestEqual(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
 		mismatchedTimeseries.Equal(emptyTimeseries)
@@ -70,6 +365,75 @@ func TestEqual(t *testing.T) {
 	}
 }
 
+func TestEqualApprox(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.EqualApprox(emptyTimeseries, 1)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		emptyTimeseries.EqualApprox(mismatchedTimeseries, 1)
+	})
+
+	if !emptyTimeseries.EqualApprox(emptyTimeseries, 0) {
+		t.Fatalf("expected emptyTimeseries to be approximately equal to emptyTimeseries")
+	}
+
+	ts1 := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0.1 + 0.2, 10, maxX},
+	}
+	ts2 := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0.3, 10.001, maxX},
+	}
+
+	if !ts1.EqualApprox(ts2, 0.01) {
+		t.Fatalf("expected ts1 to be approximately equal to ts2 within 0.01")
+	}
+
+	if ts1.EqualApprox(ts2, 0.0001) {
+		t.Fatalf("ts1 should not be approximately equal to ts2 within 0.0001")
+	}
+
+	if ts1.EqualApprox(ts1.Slice(0, 2), 1) {
+		t.Fatalf("ts1 should not be approximately equal to a series of different length")
+	}
+
+	nan := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{math.NaN()},
+	}
+	if nan.EqualApprox(nan, 1) {
+		t.Fatalf("NaN should not be approximately equal to NaN")
+	}
+}
+
+func TestString(t *testing.T) {
+	if s, expected := mismatchedTimeseries.String(), "Timeseries(len(Xs)=4, len(Ys)=2)"; s != expected {
+		t.Fatalf("expected mismatchedTimeseries to print as %s; instead got %s", expected, s)
+	}
+
+	if s, expected := emptyTimeseries.String(), "Timeseries(n=0, x=[], y=[])"; s != expected {
+		t.Fatalf("expected emptyTimeseries to print as %s; instead got %s", expected, s)
+	}
+
+	var ts Timeseries
+	for i := 1; i <= 10; i++ {
+		ts.Append(float64(i), float64(i)/2)
+	}
+
+	expected := "Timeseries(n=10, x=[1,2,3,4,5,6,7,8,9,10], y=[0.5,1,1.5,2,2.5,3,3.5,4,4.5,5])"
+	if s := fmt.Sprint(ts); s != expected {
+		t.Fatalf("expected a 10 point series to print as %s; instead got %s", expected, s)
+	}
+
+	ts.Append(11, 5.5)
+	expected = "Timeseries(n=11, x=[1,2,3,...,9,10,11], y=[0.5,1,1.5,...,4.5,5,5.5])"
+	if s := fmt.Sprint(ts); s != expected {
+		t.Fatalf("expected an 11 point series to print as %s; instead got %s", expected, s)
+	}
+}
+
 func TestAfter(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
 		mismatchedTimeseries.After(0)
@@ -157,157 +521,3068 @@ func TestBetween(t *testing.T) {
 	}
 }
 
-func TestDifference(t *testing.T) {
+func TestInterpolateAt(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
-		mismatchedTimeseries.Difference()
+		mismatchedTimeseries.InterpolateAt(0)
 	})
-
-	if x := emptyTimeseries.Difference(); !x.Equal(emptyTimeseries) {
-		t.Fatalf("expected difference of empty series to return empty series; instead got %v", x)
-	}
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.InterpolateAt(0) })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.InterpolateAtClamped(0) })
 
 	ts := Timeseries{
-		Xs: []float64{1, 2, 3},
+		Xs: []float64{1, 2, 4},
 		Ys: []float64{100.0, 50.0, 100.0},
 	}
 
-	if x := ts.Slice(1, 2).Difference(); !x.Equal(emptyTimeseries) {
-		t.Fatalf("expected difference of 1-length series to return empty series; instead got %v", x)
+	// Interpolating exactly at a sample returns that sample
+	for i, x := range ts.Xs {
+		if y := ts.InterpolateAt(x); y != ts.Ys[i] {
+			t.Fatalf("expected InterpolateAt(%v) = %v; instead got %v", x, ts.Ys[i], y)
+		}
 	}
 
-	actual := ts.Difference()
-	expected := Timeseries{
-		Xs: []float64{2, 3},
-		Ys: []float64{-50.0, 50.0},
+	if y := ts.InterpolateAt(1.5); y != 75 {
+		t.Fatalf("expected InterpolateAt(1.5) = 75; instead got %v", y)
 	}
 
-	if !actual.Equal(expected) {
-		t.Fatalf("expected ts.Difference() to return %v; instead got %v", expected, actual)
+	if y := ts.InterpolateAt(3.5); y != 87.5 {
+		t.Fatalf("expected InterpolateAt(3.5) = 87.5; instead got %v", y)
 	}
-}
-
-func TestLinearRegression(t *testing.T) {
-	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
-		mismatchedTimeseries.LinearRegression()
-	})
 
-	// Ensure that a diagonal results in a perfect fit
-	ts1 := Timeseries{
-		Xs: []float64{0, 1, 2, 3, 4, 5},
-		Ys: []float64{0, 10, 20, 30, 40, 50},
+	for _, x := range []float64{minX, 0.5, 4.5, maxX, math.NaN()} {
+		if y := ts.InterpolateAt(x); !math.IsNaN(y) {
+			t.Fatalf("expected InterpolateAt(%v) = NaN; instead got %v", x, y)
+		}
 	}
 
-	if alpha, beta, rmse := ts1.LinearRegression(); alpha != 0 || beta != 10 || rmse != 0 {
-		t.Fatalf("Expected alpha=0, beta=1, rmse=0, instead got alpha=%v, beta=%v, rmse=%v",
-			alpha, beta, rmse)
+	if y := ts.InterpolateAtClamped(0.5); y != 100 {
+		t.Fatalf("expected InterpolateAtClamped(0.5) = 100; instead got %v", y)
 	}
 
-	// Ensure that a line results in a perfect fit
-	ts2 := Timeseries{
-		Xs: []float64{0, 1, 2, 3, 4},
-		Ys: []float64{5, 5, 5, 5, 5},
+	if y := ts.InterpolateAtClamped(maxX); y != 100 {
+		t.Fatalf("expected InterpolateAtClamped(maxX) = 100; instead got %v", y)
 	}
 
-	if alpha, beta, rmse := ts2.LinearRegression(); alpha != 5 || beta != 0 || rmse != 0 {
-		t.Fatalf("Expected alpha=5, beta=0, rmse=0, instead got alpha=%v, beta=%v, rmse=%v",
-			alpha, beta, rmse)
+	if y := ts.InterpolateAtClamped(3); y != 75 {
+		t.Fatalf("expected InterpolateAtClamped(3) = 75; instead got %v", y)
 	}
 }
 
-func TestAt(t *testing.T) {
+func TestResample(t *testing.T) {
 	ts := Timeseries{
-		Xs: []float64{0, 1, 2, 3, 4, 5},
-		Ys: []float64{0, 10, 20, 30, 40, 50},
+		Xs: []float64{0, 2, 4, 6},
+		Ys: []float64{10, 20, 0, 5},
 	}
 
-	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.At(0) })
-	assertPanic(t, "timeseries: out of bounds", func() { ts.At(1337) })
+	assertPanic(t, "timeseries: invalid step", func() { ts.Resample(0, 0, 4) })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Resample(0, 1, 4) })
 
-	if x, y := ts.At(3); x != 3 || y != 30 {
-		t.Fatalf("expected ts.At(3) = 3, 30; instead got %v, %v", x, y)
+	// Resampling onto the series' own grid is the identity
+	if actual := ts.Resample(0, 2, 4); !actual.Equal(ts) {
+		t.Fatalf("expected ts.Resample(0, 2, 4) to return %v; instead got %v", ts, actual)
+	}
+
+	actual := ts.Resample(3, 1.5, 4)
+	expected := Timeseries{
+		Xs: []float64{3, 4.5, 6, 7.5},
+		Ys: []float64{10, 1.25, 5, math.NaN()},
+	}
+	for i := 0; i < 3; i++ {
+		if actual.Xs[i] != expected.Xs[i] || actual.Ys[i] != expected.Ys[i] {
+			t.Fatalf("expected ts.Resample(3, 1.5, 4) to return %v; instead got %v", expected, actual)
+		}
+	}
+
+	if x, y := actual.Last(); x != 7.5 || !math.IsNaN(y) {
+		t.Fatalf("expected the point past the end to be 7.5, NaN; instead got %v, %v", x, y)
+	}
+
+	if actual := ts.Resample(0, 1, 0); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected ts.Resample(0, 1, 0) to return an empty series; instead got %v", actual)
 	}
 }
 
-func TestFirstLast(t *testing.T) {
-	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.First() })
-	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Last() })
+func TestClone(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Clone()
+	})
 
-	ts1 := Timeseries{
-		Xs: []float64{0, 1, 2, 3, 4, 5},
-		Ys: []float64{0, 10, 20, 30, 40, 50},
+	if actual := emptyTimeseries.Clone(); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Clone() of empty series to return empty series; instead got %v", actual)
 	}
 
-	expectedFirstX, expectedFirstY := 0.0, 0.0
-	expectedLastX, expectedLastY := 5.0, 50.0
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{100.0, 50.0, 100.0},
+	}
 
-	if x, y := ts1.First(); x != expectedFirstX || y != expectedFirstY {
-		t.Fatalf("expected First() = %v, %v, instead got %v, %v", expectedFirstX, expectedFirstY, x, y)
+	clone := ts.Clone()
+	if !clone.Equal(ts) {
+		t.Fatalf("expected ts.Clone() to return %v; instead got %v", ts, clone)
 	}
 
-	if x, y := ts1.Last(); x != expectedLastX || y != expectedLastY {
-		t.Fatalf("expected Last() = %v, %v, instead got %v, %v", expectedLastX, expectedLastY, x, y)
+	clone.Xs[0], clone.Ys[0] = 1337, 1337
+	if ts.Xs[0] != 1 || ts.Ys[0] != 100 {
+		t.Fatalf("expected modifying a clone to leave ts untouched; instead got %v", ts)
 	}
 
+	// In contrast, slicing shares memory
+	after := ts.After(2)
+	after.Ys[0] = 1337
+	if ts.Ys[1] != 1337 {
+		t.Fatalf("expected modifying ts.After() to modify ts; instead got %v", ts)
+	}
 }
 
-func TestMovingAverage(t *testing.T) {
+func TestReverse(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
-		mismatchedTimeseries.MovingAverage(10)
+		mismatchedTimeseries.Reverse()
 	})
 
-	// A moving average with a window size 1 should be the identity
-	ts1 := Timeseries{
-		Xs: []float64{1, 2, 3, 4, 5, 6},
-		Ys: []float64{1, 2, 4, 8, 16, 32},
+	if actual := emptyTimeseries.Reverse(); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Reverse() of empty series to return empty series; instead got %v", actual)
 	}
 
-	if actual := ts1.MovingAverage(1); !actual.Equal(ts1) {
-		t.Fatalf("expected MovingAverage(1) to be the identity of ts1; instead got %v", actual)
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{100.0, 50.0, 25.0},
 	}
 
-	expectedForMA2 := Timeseries{
-		Xs: []float64{2, 3, 4, 5, 6},
-		Ys: []float64{
-			(1.0 + 2.0) / 2,
-			(2.0 + 4.0) / 2,
-			(4.0 + 8.0) / 2,
-			(8.0 + 16.0) / 2,
-			(16.0 + 32.0) / 2,
-		},
+	expected := Timeseries{
+		Xs: []float64{3, 2, 1},
+		Ys: []float64{25.0, 50.0, 100.0},
+	}
+	actual := ts.Reverse()
+	if !actual.Equal(expected) {
+		t.Fatalf("expected ts.Reverse() to return %v; instead got %v", expected, actual)
+	}
+
+	firstX, firstY := actual.First()
+	if lastX, lastY := ts.Last(); firstX != lastX || firstY != lastY {
+		t.Fatalf("expected ts.Reverse().First() = %v, %v; instead got %v, %v", lastX, lastY, firstX, firstY)
+	}
+
+	if ts.Xs[0] != 1 || ts.Ys[0] != 100 {
+		t.Fatalf("expected Reverse() to leave ts untouched; instead got %v", ts)
+	}
+}
+
+func TestReverseInPlace(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.ReverseInPlace()
+	})
+
+	emptyTimeseries.ReverseInPlace()
+	if emptyTimeseries.Len() != 0 {
+		t.Fatalf("expected ReverseInPlace() of empty series to leave it empty; instead got %v", emptyTimeseries)
+	}
+
+	for n := 1; n <= 4; n++ {
+		var ts Timeseries
+		for i := 0; i < n; i++ {
+			ts.Append(float64(i), float64(10*i))
+		}
+		original := ts.Clone()
+
+		ts.ReverseInPlace()
+		if expected := original.Reverse(); !ts.Equal(expected) {
+			t.Fatalf("expected ReverseInPlace() of %v to yield %v; instead got %v", original, expected, ts)
+		}
+
+		ts.ReverseInPlace()
+		if !ts.Equal(original) {
+			t.Fatalf("expected reversing %v twice to yield it back; instead got %v", original, ts)
+		}
+
+		if twice := original.Reverse().Reverse(); !twice.Equal(original) {
+			t.Fatalf("expected Reverse().Reverse() of %v to return it; instead got %v", original, twice)
+		}
+	}
+}
+
+func TestResampleAt(t *testing.T) {
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.ResampleAt([]float64{1}) })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.ResampleAtClamped([]float64{1}) })
+
+	ts := Timeseries{
+		Xs: []float64{0, 4, 8},
+		Ys: []float64{0, 100, 50},
+	}
+
+	grid := []float64{-2, 2, 4, 6, 10}
+	expected := Timeseries{
+		Xs: grid,
+		Ys: []float64{0, 50, 100, 75, 50},
+	}
+	actual := ts.ResampleAtClamped(grid)
+	if !actual.Equal(expected) {
+		t.Fatalf("expected ts.ResampleAtClamped(%v) to return %v; instead got %v", grid, expected, actual)
+	}
+
+	actual.Xs[0] = 1337
+	if grid[0] != -2 {
+		t.Fatalf("expected ResampleAtClamped() not to alias the grid; instead got %v", grid)
+	}
+
+	actual = ts.ResampleAt(grid)
+	for i, y := range actual.Ys {
+		if inRange := i > 0 && i < 4; inRange && y != expected.Ys[i] || !inRange && !math.IsNaN(y) {
+			t.Fatalf("expected ts.ResampleAt(%v) to return NaN out of range and %v in range; instead got %v", grid, expected, actual)
+		}
+	}
+}
+
+func TestSplineResample(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SplineResample(nil)
+	})
+
+	line := Timeseries{
+		Xs: []float64{0, 1, 3, 4},
+		Ys: []float64{1, 3, 7, 9},
+	}
+
+	assertPanic(t, "timeseries: too few points for a spline", func() {
+		line.Slice(0, 2).SplineResample(nil)
+	})
+
+	assertPanic(t, "timeseries: Xs not strictly increasing", func() {
+		Timeseries{Xs: []float64{0, 1, 1}, Ys: []float64{0, 1, 2}}.SplineResample(nil)
+	})
+
+	// A spline through collinear points reproduces the line
+	grid := []float64{0, 0.5, 1, 2, 2.5, 3.75, 4}
+	var expected Timeseries
+	for _, x := range grid {
+		expected.Append(x, 1+2*x)
+	}
+
+	if actual := line.SplineResample(grid); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected line.SplineResample(%v) to return %v; instead got %v", grid, expected, actual)
+	}
+
+	// The spline passes through every point of a curved series
+	curve := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4},
+		Ys: []float64{0, 1, 4, 9, 16},
+	}
+	if actual := curve.SplineResample(curve.Xs); !actual.EqualApprox(curve, 1e-12) {
+		t.Fatalf("expected curve.SplineResample(curve.Xs) to return %v; instead got %v", curve, actual)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Merge(emptyTimeseries, 0)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		emptyTimeseries.Merge(mismatchedTimeseries, 0)
+	})
+
+	ts1 := Timeseries{
+		Xs: []float64{1, 2, 4, 5},
+		Ys: []float64{10, 20, 40, 50},
+	}
+	ts2 := Timeseries{
+		Xs: []float64{3, 4, 5, 6, 7},
+		Ys: []float64{300, 400, 500, 600, 700},
+	}
+
+	expected1 := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7},
+		Ys: []float64{10, 20, -1, 40, 50, -1, -1},
+	}
+	expected2 := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7},
+		Ys: []float64{-1, -1, 300, 400, 500, 600, 700},
+	}
+
+	a, b := ts1.Merge(ts2, -1)
+	if !a.Equal(expected1) || !b.Equal(expected2) {
+		t.Fatalf("expected ts1.Merge(ts2) to return %v, %v; instead got %v, %v", expected1, expected2, a, b)
+	}
+
+	b, a = ts2.Merge(ts1, -1)
+	if !a.Equal(expected1) || !b.Equal(expected2) {
+		t.Fatalf("expected ts2.Merge(ts1) to return %v, %v; instead got %v, %v", expected2, expected1, b, a)
+	}
+
+	a, b = ts1.Merge(emptyTimeseries, -1)
+	expected2 = Timeseries{
+		Xs: ts1.Xs,
+		Ys: []float64{-1, -1, -1, -1},
+	}
+	if !a.Equal(ts1) || !b.Equal(expected2) {
+		t.Fatalf("expected ts1.Merge(emptyTimeseries) to return %v, %v; instead got %v, %v", ts1, expected2, a, b)
+	}
+}
+
+func TestFillGaps(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.FillGaps(1, FillLinear)
+	})
+
+	assertPanic(t, "timeseries: empty timeseries", func() {
+		emptyTimeseries.FillGaps(1, FillLinear)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{0, 1, 3, 4},
+		Ys: []float64{2, 4, 8, 5},
+	}
+
+	assertPanic(t, "timeseries: invalid step", func() {
+		ts.FillGaps(0, FillLinear)
+	})
+
+	assertPanic(t, "timeseries: invalid fill method", func() {
+		ts.FillGaps(1, FillMethod(-1))
+	})
+
+	cases := []struct {
+		name     string
+		method   FillMethod
+		expected []float64
+	}{
+		{"FillLinear", FillLinear, []float64{2, 4, 6, 8, 5}},
+		{"FillPrevious", FillPrevious, []float64{2, 4, 4, 8, 5}},
+		{"FillZero", FillZero, []float64{2, 4, 0, 8, 5}},
+	}
+	for _, c := range cases {
+		expected := Timeseries{
+			Xs: []float64{0, 1, 2, 3, 4},
+			Ys: c.expected,
+		}
+		if actual := ts.FillGaps(1, c.method); !actual.Equal(expected) {
+			t.Fatalf("expected FillGaps(1, %s) to return %v; instead got %v", c.name, expected, actual)
+		}
+	}
+
+	// Points off the grid are dropped, but for the last one
+	expected := Timeseries{
+		Xs: []float64{0, 3, 4},
+		Ys: []float64{2, 8, 5},
+	}
+	if actual := ts.FillGaps(3, FillLinear); !actual.Equal(expected) {
+		t.Fatalf("expected FillGaps(3, FillLinear) to return %v; instead got %v", expected, actual)
+	}
+
+	// Real points on a decimal grid are kept despite rounding errors in it,
+	// as 0.1 + 2*0.1 != 0.3
+	decimal := Timeseries{
+		Xs: []float64{0.1, 0.2, 0.3, 0.5},
+		Ys: []float64{1, 2, 3, 5},
+	}
+	for _, c := range []struct {
+		name   string
+		method FillMethod
+		y      float64
+	}{
+		{"FillLinear", FillLinear, 4},
+		{"FillPrevious", FillPrevious, 3},
+		{"FillZero", FillZero, 0},
+	} {
+		actual := decimal.FillGaps(0.1, c.method)
+		if actual.Len() != 5 || actual.Xs[2] != 0.3 || actual.Ys[2] != 3 || actual.Xs[4] != 0.5 || actual.Ys[4] != 5 {
+			t.Fatalf("expected FillGaps(0.1, %s) of %v to keep its points; instead got %v", c.name, decimal, actual)
+		}
+
+		if math.Abs(actual.Xs[3]-0.4) > 1e-12 || math.Abs(actual.Ys[3]-c.y) > 1e-12 {
+			t.Fatalf("expected FillGaps(0.1, %s) of %v to impute %v at 0.4; instead got %v", c.name, decimal, c.y, actual)
+		}
+	}
+
+	// Rounding errors in the grid neither drop nor overshoot the last point
+	for _, c := range []struct {
+		ts   Timeseries
+		step float64
+		n    int
+	}{
+		{Timeseries{Xs: []float64{0, 0.3}, Ys: []float64{0, 3}}, 0.1, 4},
+		{Timeseries{Xs: []float64{0, 7.7}, Ys: []float64{0, 7}}, 1.1, 8},
+		{Timeseries{Xs: []float64{0.1, 1.8}, Ys: []float64{1, 18}}, 0.1, 18},
+	} {
+		for _, method := range []FillMethod{FillLinear, FillPrevious, FillZero} {
+			actual := c.ts.FillGaps(c.step, method)
+			if actual.Len() != c.n || !actual.IsSorted() {
+				t.Fatalf("expected FillGaps(%v, %v) of %v to return %d sorted points; instead got %v", c.step, method, c.ts, c.n, actual)
+			}
+
+			if x, y := actual.Last(); x != c.ts.Xs[1] || y != c.ts.Ys[1] {
+				t.Fatalf("expected FillGaps(%v, %v) of %v to keep the last point; instead got %v", c.step, method, c.ts, actual)
+			}
+		}
+	}
+
+	// A single large gap is filled at step resolution, leaving the points on
+	// either side of it in place
+	gappy := Timeseries{
+		Xs: []float64{0, 0.5, 1, 3.5, 4},
+		Ys: []float64{2, 3, 4, 9, 10},
+	}
+	gaps := []struct {
+		name     string
+		method   FillMethod
+		expected []float64
+	}{
+		{"FillLinear", FillLinear, []float64{5, 6, 7, 8}},
+		{"FillPrevious", FillPrevious, []float64{4, 4, 4, 4}},
+		{"FillZero", FillZero, []float64{0, 0, 0, 0}},
+	}
+	for _, c := range gaps {
+		expected := Timeseries{
+			Xs: []float64{0, 0.5, 1, 1.5, 2, 2.5, 3, 3.5, 4},
+			Ys: append(append([]float64{2, 3, 4}, c.expected...), 9, 10),
+		}
+		actual := gappy.FillGaps(0.5, c.method)
+		if inserted := actual.Len() - gappy.Len(); inserted != 4 {
+			t.Fatalf("expected FillGaps(0.5, %s) to insert 4 points; instead inserted %d", c.name, inserted)
+		}
+		if !actual.Equal(expected) {
+			t.Fatalf("expected FillGaps(0.5, %s) to return %v; instead got %v", c.name, expected, actual)
+		}
+	}
+
+	single := Timeseries{
+		Xs: []float64{5},
+		Ys: []float64{1},
+	}
+	if actual := single.FillGaps(1, FillZero); !actual.Equal(single) {
+		t.Fatalf("expected FillGaps() of a single point to return %v; instead got %v", single, actual)
+	}
+}
+
+func TestFillLargeGaps(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.FillLargeGaps(1, FillLinear)
+	})
+
+	if actual := emptyTimeseries.FillLargeGaps(1, FillLinear); actual.Len() != 0 {
+		t.Fatalf("expected FillLargeGaps() of empty series to return empty series; instead got %v", actual)
+	}
+
+	// Off-grid samples, with gaps of 1.5 and 2.5
+	ts := Timeseries{
+		Xs: []float64{0, 1.5, 4},
+		Ys: []float64{0, 3, 8},
+	}
+
+	assertPanic(t, "timeseries: invalid step", func() {
+		ts.FillLargeGaps(0, FillLinear)
+	})
+
+	assertPanic(t, "timeseries: invalid fill method", func() {
+		ts.FillLargeGaps(1, FillZero+1)
+	})
+
+	cases := []struct {
+		name     string
+		method   FillMethod
+		expected []float64
+	}{
+		{"FillLinear", FillLinear, []float64{0, 2, 3, 5, 7, 8}},
+		{"FillPrevious", FillPrevious, []float64{0, 0, 3, 3, 3, 8}},
+		{"FillZero", FillZero, []float64{0, 0, 3, 0, 0, 8}},
+	}
+	for _, c := range cases {
+		expected := Timeseries{
+			Xs: []float64{0, 1, 1.5, 2.5, 3.5, 4},
+			Ys: c.expected,
+		}
+		actual := ts.FillLargeGaps(1, c.method)
+		if inserted := actual.Len() - ts.Len(); inserted != 3 {
+			t.Fatalf("expected FillLargeGaps(1, %s) to insert 3 points; instead inserted %d", c.name, inserted)
+		}
+		if !actual.Equal(expected) {
+			t.Fatalf("expected FillLargeGaps(1, %s) to return %v; instead got %v", c.name, expected, actual)
+		}
+	}
+
+	// Gaps no larger than maxStep are left alone
+	if actual := ts.FillLargeGaps(2.5, FillLinear); !actual.Equal(ts) {
+		t.Fatalf("expected FillLargeGaps(2.5) to return %v unchanged; instead got %v", ts, actual)
+	}
+
+	// No point is inserted a rounding error away from the end of a gap
+	rounded := Timeseries{
+		Xs: []float64{0, 0.3},
+		Ys: []float64{0, 3},
+	}
+	expected := Timeseries{
+		Xs: []float64{0, 0.1, 0.2, 0.3},
+		Ys: []float64{0, 0, 0, 3},
+	}
+	if actual := rounded.FillLargeGaps(0.1, FillZero); !actual.Equal(expected) {
+		t.Fatalf("expected FillLargeGaps(0.1) of %v to return %v; instead got %v", rounded, expected, actual)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Difference()
+	})
+
+	if x := emptyTimeseries.Difference(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected difference of empty series to return empty series; instead got %v", x)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{100.0, 50.0, 100.0},
+	}
+
+	if x := ts.Slice(1, 2).Difference(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected difference of 1-length series to return empty series; instead got %v", x)
+	}
+
+	actual := ts.Difference()
+	expected := Timeseries{
+		Xs: []float64{2, 3},
+		Ys: []float64{-50.0, 50.0},
+	}
+
+	if !actual.Equal(expected) {
+		t.Fatalf("expected ts.Difference() to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestDiffN(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.DiffN(1)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{100.0, 50.0, 100.0},
+	}
+
+	assertPanic(t, "timeseries: invalid lag", func() { ts.DiffN(0) })
+
+	if expected, actual := ts.Difference(), ts.DiffN(1); !actual.Equal(expected) {
+		t.Fatalf("expected ts.DiffN(1) to return %v; instead got %v", expected, actual)
+	}
+
+	for _, lag := range []int{3, 4} {
+		if actual := ts.DiffN(lag); !actual.Equal(emptyTimeseries) {
+			t.Fatalf("expected ts.DiffN(%v) to return an empty series; instead got %v", lag, actual)
+		}
+	}
+
+	// A season of 4 on top of a trend of 1 per step
+	seasonal := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		Ys: []float64{5, 1, 7, 3, 9, 5, 11, 7, 13, 9},
+	}
+
+	expected := Timeseries{
+		Xs: []float64{4, 5, 6, 7, 8, 9},
+		Ys: []float64{4, 4, 4, 4, 4, 4},
+	}
+	if actual := seasonal.DiffN(4); !actual.Equal(expected) {
+		t.Fatalf("expected seasonal.DiffN(4) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestDerivative(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Derivative()
+	})
+
+	if x := emptyTimeseries.Derivative(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected derivative of empty series to return empty series; instead got %v", x)
+	}
+
+	// y = 3x + 1, sampled unevenly
+	ramp := Timeseries{
+		Xs: []float64{0, 1, 3, 7},
+		Ys: []float64{1, 4, 10, 22},
+	}
+
+	if x := ramp.Slice(0, 1).Derivative(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected derivative of 1-length series to return empty series; instead got %v", x)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{1, 3, 7},
+		Ys: []float64{3, 3, 3},
+	}
+	if actual := ramp.Derivative(); !actual.Equal(expected) {
+		t.Fatalf("expected ramp.Derivative() to return %v; instead got %v", expected, actual)
+	}
+
+	// Zero-width intervals yield ±Inf, or NaN when the Ys agree too
+	duplicated := Timeseries{
+		Xs: []float64{0, 1, 1, 1, 1},
+		Ys: []float64{0, 1, 2, 0, 0},
+	}
+	actual := duplicated.Derivative()
+	if y := actual.Ys[1]; !math.IsInf(y, 1) {
+		t.Fatalf("expected a rising zero-width interval to yield +Inf; instead got %v", y)
+	}
+	if y := actual.Ys[2]; !math.IsInf(y, -1) {
+		t.Fatalf("expected a falling zero-width interval to yield -Inf; instead got %v", y)
+	}
+	if y := actual.Ys[3]; !math.IsNaN(y) {
+		t.Fatalf("expected a flat zero-width interval to yield NaN; instead got %v", y)
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.PercentChange()
+	})
+
+	if x := emptyTimeseries.PercentChange(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected percent change of empty series to return empty series; instead got %v", x)
+	}
+
+	doubling := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{1, 2, 4, 8},
+	}
+
+	if x := doubling.Slice(0, 1).PercentChange(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected percent change of 1-length series to return empty series; instead got %v", x)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{2, 3, 4},
+		Ys: []float64{1, 1, 1},
+	}
+	if actual := doubling.PercentChange(); !actual.Equal(expected) {
+		t.Fatalf("expected doubling.PercentChange() to return %v; instead got %v", expected, actual)
+	}
+
+	zeros := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{4, 0, 0, 2},
+	}
+	actual := zeros.PercentChange()
+	if y := actual.Ys[0]; y != -1 {
+		t.Fatalf("expected a drop to zero to be a change of -1; instead got %v", y)
+	}
+	if y := actual.Ys[1]; !math.IsNaN(y) {
+		t.Fatalf("expected a change from zero to zero to be NaN; instead got %v", y)
+	}
+	if y := actual.Ys[2]; !math.IsInf(y, 1) {
+		t.Fatalf("expected a rise from zero to be +Inf; instead got %v", y)
+	}
+}
+
+func TestShift(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Shift(0)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{10, 20, 30, 40},
+	}
+
+	if actual := ts.Shift(0); !actual.Equal(ts) {
+		t.Fatalf("expected Shift(0) to be the identity of ts; instead got %v", actual)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{2, 3, 4},
+		Ys: []float64{10, 20, 30},
+	}
+	if actual := ts.Shift(1); !actual.Equal(expected) {
+		t.Fatalf("expected Shift(1) to return %v; instead got %v", expected, actual)
+	}
+
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{20, 30, 40},
+	}
+	if actual := ts.Shift(-1); !actual.Equal(expected) {
+		t.Fatalf("expected Shift(-1) to return %v; instead got %v", expected, actual)
+	}
+
+	for _, n := range []int{4, -4, 100} {
+		if actual := ts.Shift(n); !actual.Equal(emptyTimeseries) {
+			t.Fatalf("expected Shift(%v) to return an empty series; instead got %v", n, actual)
+		}
+	}
+
+	actual := ts.Shift(0)
+	actual.Ys[0] = 1337
+	if ts.Ys[0] != 10 {
+		t.Fatalf("expected Shift() to leave ts untouched; instead got %v", ts)
+	}
+}
+
+func TestCumulativeSum(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.CumulativeSum()
+	})
+
+	if x := emptyTimeseries.CumulativeSum(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected cumulative sum of empty series to return empty series; instead got %v", x)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{100.0, -50.0, 25.0, 5.0},
+	}
+
+	actual := ts.CumulativeSum()
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{100.0, 50.0, 75.0, 80.0},
+	}
+
+	if !actual.Equal(expected) {
+		t.Fatalf("expected ts.CumulativeSum() to return %v; instead got %v", expected, actual)
+	}
+
+	if _, y := actual.Last(); y != ts.Sum() {
+		t.Fatalf("expected the last Y of ts.CumulativeSum() to equal ts.Sum() = %v; instead got %v", ts.Sum(), y)
+	}
+
+	// CumulativeSum inverts Difference, up to the dropped first point
+	restored := ts.Difference().CumulativeSum()
+	_, y0 := ts.First()
+	for i := 0; i < restored.Len(); i++ {
+		if x, y := restored.At(i); x != ts.Xs[i+1] || y+y0 != ts.Ys[i+1] {
+			t.Fatalf("expected Difference().CumulativeSum() to reconstruct %v; instead got %v", ts, restored)
+		}
+	}
+
+	// The input must not be aliased
+	actual.Xs[0], actual.Ys[0] = 1337, 1337
+	if ts.Xs[0] != 1 || ts.Ys[0] != 100 {
+		t.Fatalf("expected ts.CumulativeSum() to leave ts unmodified; instead got %v", ts)
+	}
+}
+
+func TestIntegrate(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Integrate()
+	})
+
+	if area := emptyTimeseries.Integrate(); area != 0 {
+		t.Fatalf("expected Integrate() of empty series = 0, instead got %v", area)
+	}
+
+	// A constant function with non-uniform spacing; area = height*width
+	constant := Timeseries{
+		Xs: []float64{0, 1, 4, 10},
+		Ys: []float64{5, 5, 5, 5},
+	}
+
+	if area := constant.Integrate(); area != 50 {
+		t.Fatalf("expected Integrate() = 50, instead got %v", area)
+	}
+
+	if area := constant.Slice(0, 1).Integrate(); area != 0 {
+		t.Fatalf("expected Integrate() of a single item = 0, instead got %v", area)
+	}
+
+	// y = x over [0, 4]; area = 4*4/2
+	diagonal := Timeseries{
+		Xs: []float64{0, 0.5, 3, 4},
+		Ys: []float64{0, 0.5, 3, 4},
+	}
+
+	if area := diagonal.Integrate(); area != 8 {
+		t.Fatalf("expected Integrate() = 8, instead got %v", area)
+	}
+}
+
+func TestCumulativeIntegral(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.CumulativeIntegral()
+	})
+
+	if x := emptyTimeseries.CumulativeIntegral(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected cumulative integral of empty series to return empty series; instead got %v", x)
+	}
+
+	// y = x, sampled unevenly
+	diagonal := Timeseries{
+		Xs: []float64{0, 0.5, 3, 4},
+		Ys: []float64{0, 0.5, 3, 4},
+	}
+
+	if x := diagonal.Slice(0, 1).CumulativeIntegral(); !x.Equal(emptyTimeseries) {
+		t.Fatalf("expected cumulative integral of 1-length series to return empty series; instead got %v", x)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{0.5, 3, 4},
+		Ys: []float64{0.125, 4.5, 8},
+	}
+	actual := diagonal.CumulativeIntegral()
+	if !actual.Equal(expected) {
+		t.Fatalf("expected CumulativeIntegral() to return %v; instead got %v", expected, actual)
+	}
+
+	if _, y := actual.Last(); y != diagonal.Integrate() {
+		t.Fatalf("expected the last Y of CumulativeIntegral() to equal Integrate() = %v; instead got %v", diagonal.Integrate(), y)
+	}
+}
+
+func TestLinearRegression(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.LinearRegression()
+	})
+
+	// Ensure that a diagonal results in a perfect fit
+	ts1 := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5},
+		Ys: []float64{0, 10, 20, 30, 40, 50},
+	}
+
+	if alpha, beta, rmse := ts1.LinearRegression(); alpha != 0 || beta != 10 || rmse != 0 {
+		t.Fatalf("Expected alpha=0, beta=1, rmse=0, instead got alpha=%v, beta=%v, rmse=%v",
+			alpha, beta, rmse)
+	}
+
+	// Ensure that a line results in a perfect fit
+	ts2 := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4},
+		Ys: []float64{5, 5, 5, 5, 5},
+	}
+
+	if alpha, beta, rmse := ts2.LinearRegression(); alpha != 5 || beta != 0 || rmse != 0 {
+		t.Fatalf("Expected alpha=5, beta=0, rmse=0, instead got alpha=%v, beta=%v, rmse=%v",
+			alpha, beta, rmse)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Forecast(1, 1)
+	})
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Forecast(1, 1) })
+
+	// y = 10x - 5
+	ts := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5},
+		Ys: []float64{-5, 5, 15, 25, 35, 45},
+	}
+
+	if actual := ts.Forecast(0, 1); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Forecast(0, 1) to return an empty series; instead got %v", actual)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{5.5, 6, 6.5},
+		Ys: []float64{50, 55, 60},
+	}
+	if actual := ts.Forecast(3, 0.5); !actual.EqualApprox(expected, 1e-9) {
+		t.Fatalf("expected Forecast(3, 0.5) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	const period = 12
+	seasonal := func(i int) float64 {
+		return 100 + 0.5*float64(i) + 10*math.Sin(2*math.Pi*float64(i)/period)
+	}
+
+	var ts Timeseries
+	for i := 0; i < 8*period; i++ {
+		ts.Append(float64(i), seasonal(i))
+	}
+
+	assertPanic(t, "timeseries: smoothing factor out of range", func() { ts.HoltWinters(-0.1, 0.1, 0.1, period, 1) })
+	assertPanic(t, "timeseries: smoothing factor out of range", func() { ts.HoltWinters(0.5, 1.1, 0.1, period, 1) })
+	assertPanic(t, "timeseries: smoothing factor out of range", func() { ts.HoltWinters(0.5, 0.1, math.NaN(), period, 1) })
+	assertPanic(t, "timeseries: invalid period", func() { ts.HoltWinters(0.5, 0.1, 0.1, 1, 1) })
+	assertPanic(t, "timeseries: too few points for the period", func() {
+		ts.Slice(0, 2*period-1).HoltWinters(0.5, 0.1, 0.1, period, 1)
+	})
+
+	if actual := ts.HoltWinters(0.5, 0.1, 0.1, period, 0); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected HoltWinters() with a zero horizon to return an empty series; instead got %v", actual)
+	}
+
+	forecast := ts.HoltWinters(0.5, 0.1, 0.3, period, period)
+	if n := forecast.Len(); n != period {
+		t.Fatalf("expected HoltWinters() to forecast %v points; instead got %v", period, n)
+	}
+
+	for i, x := range forecast.Xs {
+		j := ts.Len() + i
+		if x != float64(j) {
+			t.Fatalf("expected forecast point %v at x = %v; instead got %v", i, j, x)
+		}
+
+		if y := forecast.Ys[i]; math.Abs(y-seasonal(j)) > 0.01 {
+			t.Fatalf("expected forecast point %v to be within 0.01 of %v; instead got %v", i, seasonal(j), y)
+		}
+	}
+}
+
+func TestAt(t *testing.T) {
+	ts := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5},
+		Ys: []float64{0, 10, 20, 30, 40, 50},
+	}
+
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.At(0) })
+	assertPanic(t, "timeseries: out of bounds", func() { ts.At(1337) })
+
+	if x, y := ts.At(3); x != 3 || y != 30 {
+		t.Fatalf("expected ts.At(3) = 3, 30; instead got %v, %v", x, y)
+	}
+}
+
+func TestTryAt(t *testing.T) {
+	if _, _, err := mismatchedTimeseries.TryAt(0); err != ErrLengthMismatch {
+		t.Fatalf("expected mismatchedTimeseries.TryAt(0) to return ErrLengthMismatch; instead got %v", err)
+	}
+
+	if _, _, err := emptyTimeseries.TryAt(0); err != ErrEmpty {
+		t.Fatalf("expected emptyTimeseries.TryAt(0) to return ErrEmpty; instead got %v", err)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5},
+		Ys: []float64{0, 10, 20, 30, 40, 50},
+	}
+
+	for _, i := range []int{-1, 6, 1337} {
+		if _, _, err := ts.TryAt(i); err != ErrOutOfBounds {
+			t.Fatalf("expected ts.TryAt(%v) to return ErrOutOfBounds; instead got %v", i, err)
+		}
+	}
+
+	if x, y, err := ts.TryAt(3); x != 3 || y != 30 || err != nil {
+		t.Fatalf("expected ts.TryAt(3) = 3, 30, nil; instead got %v, %v, %v", x, y, err)
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.First() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Last() })
+
+	ts1 := Timeseries{
+		Xs: []float64{0, 1, 2, 3, 4, 5},
+		Ys: []float64{0, 10, 20, 30, 40, 50},
+	}
+
+	expectedFirstX, expectedFirstY := 0.0, 0.0
+	expectedLastX, expectedLastY := 5.0, 50.0
+
+	if x, y := ts1.First(); x != expectedFirstX || y != expectedFirstY {
+		t.Fatalf("expected First() = %v, %v, instead got %v, %v", expectedFirstX, expectedFirstY, x, y)
+	}
+
+	if x, y := ts1.Last(); x != expectedLastX || y != expectedLastY {
+		t.Fatalf("expected Last() = %v, %v, instead got %v, %v", expectedLastX, expectedLastY, x, y)
+	}
+
+}
+
+func TestRangeSpan(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() { mismatchedTimeseries.Range() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Range() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Span() })
+
+	ts := Timeseries{
+		Xs: []float64{-1.5, 2, 6.5},
+		Ys: []float64{30, 10, 20},
+	}
+
+	if minX, maxX := ts.Range(); minX != -1.5 || maxX != 6.5 {
+		t.Fatalf("expected Range() = -1.5, 6.5; instead got %v, %v", minX, maxX)
+	}
+
+	if span := ts.Span(); span != 8 {
+		t.Fatalf("expected Span() = 8; instead got %v", span)
+	}
+
+	if span := ts.Slice(1, 2).Span(); span != 0 {
+		t.Fatalf("expected Span() of a single point = 0; instead got %v", span)
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.MovingAverage(10)
+	})
+
+	assertPanic(t, "timeseries: invalid window size", func() {
+		emptyTimeseries.MovingAverage(1)
+	})
+
+	// A moving average with a window size 1 should be the identity
+	ts1 := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{1, 2, 4, 8, 16, 32},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts1.MovingAverage(0) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts1.MovingAverage(7) })
+
+	if actual := ts1.MovingAverage(1); !actual.Equal(ts1) {
+		t.Fatalf("expected MovingAverage(1) to be the identity of ts1; instead got %v", actual)
+	}
+
+	expectedForMA2 := Timeseries{
+		Xs: []float64{2, 3, 4, 5, 6},
+		Ys: []float64{
+			(1.0 + 2.0) / 2,
+			(2.0 + 4.0) / 2,
+			(4.0 + 8.0) / 2,
+			(8.0 + 16.0) / 2,
+			(16.0 + 32.0) / 2,
+		},
 	}
 	if actual := ts1.MovingAverage(2); !actual.Equal(expectedForMA2) {
 		t.Fatalf("expected MovingAverage(2) to return %v; instead got %v", expectedForMA2, actual)
 	}
 
-	expectedForMA4 := Timeseries{
-		Xs: []float64{4, 5, 6},
-		Ys: []float64{
-			(1.0 + 2.0 + 4.0 + 8.0) / 4,
-			(2.0 + 4.0 + 8.0 + 16.0) / 4,
-			(4.0 + 8.0 + 16.0 + 32.0) / 4,
-		},
+	expectedForMA4 := Timeseries{
+		Xs: []float64{4, 5, 6},
+		Ys: []float64{
+			(1.0 + 2.0 + 4.0 + 8.0) / 4,
+			(2.0 + 4.0 + 8.0 + 16.0) / 4,
+			(4.0 + 8.0 + 16.0 + 32.0) / 4,
+		},
+	}
+	if actual := ts1.MovingAverage(4); !actual.Equal(expectedForMA4) {
+		t.Fatalf("expected MovingAverage(4) to return %v; instead got %v", expectedForMA4, actual)
+	}
+
+	expectedForMA6 := Timeseries{
+		Xs: []float64{6},
+		Ys: []float64{(1.0 + 2.0 + 4.0 + 8.0 + 16.0 + 32.0) / 6},
+	}
+	if actual := ts1.MovingAverage(6); !actual.Equal(expectedForMA6) {
+		t.Fatalf("expected MovingAverage(6) to return %v; instead got %v", expectedForMA6, actual)
+	}
+
+	// The result must not alias the input
+	actual := ts1.MovingAverage(1)
+	actual.Ys[0] = 1337
+	if ts1.Ys[0] != 1 {
+		t.Fatalf("expected MovingAverage() to return a fresh series; input was modified to %v", ts1)
+	}
+
+}
+
+func TestWeightedMovingAverage(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.WeightedMovingAverage([]float64{1})
+	})
+
+	ts1 := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{1, 2, 4, 8, 16, 32},
+	}
+
+	assertPanic(t, "timeseries: empty weights", func() { ts1.WeightedMovingAverage(nil) })
+	assertPanic(t, "timeseries: negative weight", func() { ts1.WeightedMovingAverage([]float64{1, -1}) })
+	assertPanic(t, "timeseries: weights sum to zero", func() { ts1.WeightedMovingAverage([]float64{0, 0}) })
+	assertPanic(t, "timeseries: invalid window size", func() {
+		ts1.WeightedMovingAverage([]float64{1, 1, 1, 1, 1, 1, 1})
+	})
+
+	// Uniform weights must reproduce MovingAverage, whatever their scale
+	for window := 1; window <= ts1.Len(); window++ {
+		weights := make([]float64, window)
+		for i := range weights {
+			weights[i] = 0.5
+		}
+
+		expected := ts1.MovingAverage(window)
+		if actual := ts1.WeightedMovingAverage(weights); !actual.Equal(expected) {
+			t.Fatalf("expected uniform WeightedMovingAverage(%v) to return %v; instead got %v", weights, expected, actual)
+		}
+	}
+
+	// The last weight applies to the most recent point
+	expected := Timeseries{
+		Xs: []float64{2, 3, 4, 5, 6},
+		Ys: []float64{
+			(1.0*1 + 3.0*2) / 4,
+			(1.0*2 + 3.0*4) / 4,
+			(1.0*4 + 3.0*8) / 4,
+			(1.0*8 + 3.0*16) / 4,
+			(1.0*16 + 3.0*32) / 4,
+		},
+	}
+	if actual := ts1.WeightedMovingAverage([]float64{1, 3}); !actual.Equal(expected) {
+		t.Fatalf("expected WeightedMovingAverage([1 3]) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestExponentialMovingAverage(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.ExponentialMovingAverage(0.5)
+	})
+
+	ts1 := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{8, 16, 0, 4},
+	}
+
+	assertPanic(t, "timeseries: alpha out of range", func() { ts1.ExponentialMovingAverage(0) })
+	assertPanic(t, "timeseries: alpha out of range", func() { ts1.ExponentialMovingAverage(1.5) })
+	assertPanic(t, "timeseries: invalid span", func() { ts1.ExponentialMovingAverageSpan(0) })
+
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{8, 12, 6, 5},
+	}
+	if actual := ts1.ExponentialMovingAverage(0.5); !actual.Equal(expected) {
+		t.Fatalf("expected ExponentialMovingAverage(0.5) to return %v; instead got %v", expected, actual)
+	}
+
+	if actual := emptyTimeseries.ExponentialMovingAverage(0.5); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected ExponentialMovingAverage() of empty series to return empty series; instead got %v", actual)
+	}
+
+	// An alpha of 1 does no smoothing at all
+	if actual := ts1.ExponentialMovingAverage(1); !actual.Equal(ts1) {
+		t.Fatalf("expected ExponentialMovingAverage(1) to be the identity of ts1; instead got %v", actual)
+	}
+
+	// Smoothing a constant series leaves it constant
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{7, 7, 7, 7},
+	}
+	if actual := constant.ExponentialMovingAverage(0.3); !actual.Equal(constant) {
+		t.Fatalf("expected ExponentialMovingAverage(0.3) of a constant series to be constant; instead got %v", actual)
+	}
+
+	// A span of 3 corresponds to alpha = 2/(3+1) = 0.5
+	if actual := ts1.ExponentialMovingAverageSpan(3); !actual.Equal(expected) {
+		t.Fatalf("expected ExponentialMovingAverageSpan(3) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestGaussianSmooth(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.GaussianSmooth(1)
+	})
+
+	assertPanic(t, "timeseries: invalid sigma", func() {
+		emptyTimeseries.GaussianSmooth(0)
+	})
+
+	assertPanic(t, "timeseries: invalid sigma", func() {
+		emptyTimeseries.GaussianSmooth(math.Inf(1))
+	})
+
+	assertPanic(t, "timeseries: invalid sigma", func() {
+		emptyTimeseries.GaussianSmooth(math.NaN())
+	})
+
+	if actual := emptyTimeseries.GaussianSmooth(1); actual.Len() != 0 {
+		t.Fatalf("expected GaussianSmooth() of empty series to return empty series; instead got %v", actual)
+	}
+
+	// A kernel reaching past both ends of the series is reflected as needed
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{7, 7, 7, 7, 7},
+	}
+	for _, sigma := range []float64{0.5, 1, 3} {
+		if actual := constant.GaussianSmooth(sigma); !actual.EqualApprox(constant, 1e-12) {
+			t.Fatalf("expected GaussianSmooth(%v) of %v to return it unchanged; instead got %v", sigma, constant, actual)
+		}
+	}
+
+	// A kernel much wider than the series averages its reflection, in which
+	// the inner points appear twice as often as the ends
+	short := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0, 4, 0},
+	}
+	for _, sigma := range []float64{1e6, math.MaxFloat64} {
+		actual := short.GaussianSmooth(sigma)
+		for _, y := range actual.Ys {
+			if math.Abs(y-2) > 1e-6 {
+				t.Fatalf("expected GaussianSmooth(%v) of %v to flatten it to 2; instead got %v", sigma, short, actual)
+			}
+		}
+	}
+
+	// Folding the kernel onto the reflection period does not change the result
+	folded := short.GaussianSmooth(2)
+	expected := make([]float64, 3)
+	for i := range expected {
+		var sum float64
+		for d := -6; d <= 6; d++ {
+			w := math.Exp(-float64(d*d) / 8)
+			j := i + d
+			for j < 0 || j > 2 {
+				if j < 0 {
+					j = -j
+				} else {
+					j = 4 - j
+				}
+			}
+			expected[i] += w * short.Ys[j]
+			sum += w
+		}
+		expected[i] /= sum
+	}
+	if !folded.EqualApprox(Timeseries{Xs: short.Xs, Ys: expected}, 1e-12) {
+		t.Fatalf("expected GaussianSmooth(2) of %v to return %v; instead got %v", short, expected, folded)
+	}
+
+	single := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{4},
+	}
+	if actual := single.GaussianSmooth(2); !actual.Equal(single) {
+		t.Fatalf("expected GaussianSmooth() of %v to return it unchanged; instead got %v", single, actual)
+	}
+
+	var spike Timeseries
+	for i := 0; i < 21; i++ {
+		spike.Append(float64(i), 0)
+	}
+	spike.Ys[10] = 1
+
+	actual := spike.GaussianSmooth(2)
+	if actual.Len() != spike.Len() || actual.Ys[10] >= 1 {
+		t.Fatalf("expected GaussianSmooth(2) to spread the spike of %v; instead got %v", spike, actual)
+	}
+
+	var sum float64
+	for i, y := range actual.Ys {
+		if actual.Xs[i] != spike.Xs[i] {
+			t.Fatalf("expected GaussianSmooth() to preserve the Xs of %v; instead got %v", spike, actual)
+		}
+
+		if y != actual.Ys[20-i] {
+			t.Fatalf("expected GaussianSmooth(2) to spread the spike symmetrically; instead got %v", actual)
+		}
+
+		if (i < 4 || i > 16) && y != 0 {
+			t.Fatalf("expected GaussianSmooth(2) to truncate the kernel at 6 points; instead got %v", actual)
+		}
+
+		sum += y
+	}
+
+	if math.Abs(sum-1) > 1e-12 {
+		t.Fatalf("expected GaussianSmooth(2) to preserve the sum of the Ys; instead got %v", sum)
+	}
+}
+
+func TestRollingSum(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.RollingSum(1)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{0.1, 2, -4, 8.3, 16, 32},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingSum(0) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingSum(7) })
+
+	expected := Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{0.1 + 2 - 4, 2 - 4 + 8.3, -4 + 8.3 + 16, 8.3 + 16 + 32},
+	}
+	if actual := ts.RollingSum(3); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected RollingSum(3) to return %v; instead got %v", expected, actual)
+	}
+
+	for window := 1; window <= ts.Len(); window++ {
+		expected := ts.MovingAverage(window)
+		if actual := ts.RollingSum(window).Scale(1 / float64(window)); !actual.EqualApprox(expected, 1e-12) {
+			t.Fatalf("expected RollingSum(%v)/%v to return %v; instead got %v", window, window, expected, actual)
+		}
+	}
+}
+
+func TestRollingStdDev(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.RollingStdDev(2)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{1, 2, 3, 7, 7, 7},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingStdDev(0) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingStdDev(7) })
+
+	// The population variance of [1 2 3] is 2/3, of [2 3 7] is 14/3 and of
+	// [3 7 7] is 32/9
+	expected := Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{math.Sqrt(2.0 / 3), math.Sqrt(14.0 / 3), math.Sqrt(32.0 / 9), 0},
+	}
+	if actual := ts.RollingStdDev(3); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected RollingStdDev(3) to return %v; instead got %v", expected, actual)
+	}
+
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{5, 5, 5, 5},
+	}
+	for window := 1; window <= constant.Len(); window++ {
+		for _, y := range constant.RollingStdDev(window).Ys {
+			if y != 0 {
+				t.Fatalf("expected RollingStdDev(%d) over a constant series to be 0; instead got %v", window, y)
+			}
+		}
+	}
+}
+
+func TestRollingSampleStdDev(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.RollingSampleStdDev(2)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{1, 2, 3, 7, 7, 7},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingSampleStdDev(1) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingSampleStdDev(7) })
+
+	// The sample variance of [1 2 3] is 1, of [2 3 7] is 7 and of [3 7 7] is 16/3
+	expected := Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{1, math.Sqrt(7), math.Sqrt(16.0 / 3), 0},
+	}
+	if actual := ts.RollingSampleStdDev(3); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected RollingSampleStdDev(3) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestBollingerBands(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.BollingerBands(2, 2)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{1, 3, 1, 3, 8, 8},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.BollingerBands(0, 2) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.BollingerBands(7, 2) })
+	assertPanic(t, "timeseries: invalid band width", func() { ts.BollingerBands(2, -1) })
+
+	middle, upper, lower := ts.BollingerBands(2, 2)
+
+	// The windows [1 3], [3 1] and [1 3] have a mean of 2 and a population
+	// standard deviation of 1, [3 8] of 5.5 and 2.5, and [8 8] of 8 and 0
+	expected := Timeseries{
+		Xs: []float64{2, 3, 4, 5, 6},
+		Ys: []float64{2, 2, 2, 5.5, 8},
+	}
+	if !middle.Equal(expected) {
+		t.Fatalf("expected the middle band to be %v; instead got %v", expected, middle)
+	}
+
+	expected.Ys = []float64{4, 4, 4, 10.5, 8}
+	if !upper.Equal(expected) {
+		t.Fatalf("expected the upper band to be %v; instead got %v", expected, upper)
+	}
+
+	expected.Ys = []float64{0, 0, 0, 0.5, 8}
+	if !lower.Equal(expected) {
+		t.Fatalf("expected the lower band to be %v; instead got %v", expected, lower)
+	}
+
+	for i := range middle.Xs {
+		if upper.Xs[i] != middle.Xs[i] || lower.Xs[i] != middle.Xs[i] {
+			t.Fatalf("expected the bands to share Xs; instead got %v, %v and %v", middle.Xs, upper.Xs, lower.Xs)
+		}
+
+		if !(upper.Ys[i] >= middle.Ys[i] && middle.Ys[i] >= lower.Ys[i]) {
+			t.Fatalf("expected upper >= middle >= lower at index %d; instead got %v, %v and %v", i, upper.Ys[i], middle.Ys[i], lower.Ys[i])
+		}
+	}
+}
+
+func TestRollingMaxMin(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.RollingMax(1)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{3, 1, 4, 1, 5, 9},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingMax(0) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingMin(7) })
+
+	// A window of 1 is the identity
+	if actual := ts.RollingMax(1); !actual.Equal(ts) {
+		t.Fatalf("expected RollingMax(1) to be the identity of ts; instead got %v", actual)
+	}
+
+	if actual := ts.RollingMin(1); !actual.Equal(ts) {
+		t.Fatalf("expected RollingMin(1) to be the identity of ts; instead got %v", actual)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{4, 4, 5, 9},
+	}
+	if actual := ts.RollingMax(3); !actual.Equal(expected) {
+		t.Fatalf("expected RollingMax(3) to return %v; instead got %v", expected, actual)
+	}
+
+	expected = Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{1, 1, 1, 1},
+	}
+	if actual := ts.RollingMin(3); !actual.Equal(expected) {
+		t.Fatalf("expected RollingMin(3) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestRollingApply(t *testing.T) {
+	rollingMax := func(sub Timeseries) float64 {
+		_, y := sub.Max()
+		return y
+	}
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.RollingApply(1, rollingMax)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{3, 1, 4, 1, 5, 9},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingApply(0, rollingMax) })
+	assertPanic(t, "timeseries: invalid window size", func() { ts.RollingApply(7, rollingMax) })
+
+	for window := 1; window <= ts.Len(); window++ {
+		expected := ts.RollingMax(window)
+		if actual := ts.RollingApply(window, rollingMax); !actual.Equal(expected) {
+			t.Fatalf("expected RollingApply(%v, rollingMax) to return %v; instead got %v", window, expected, actual)
+		}
+	}
+
+	// Each window spans the right points
+	span := func(sub Timeseries) float64 {
+		first, _ := sub.First()
+		last, _ := sub.Last()
+		return last - first
+	}
+	expected := Timeseries{
+		Xs: []float64{3, 4, 5, 6},
+		Ys: []float64{2, 2, 2, 2},
+	}
+	if actual := ts.RollingApply(3, span); !actual.Equal(expected) {
+		t.Fatalf("expected RollingApply(3, span) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.IsSorted()
+	})
+
+	if !emptyTimeseries.IsSorted() {
+		t.Fatalf("expected emptyTimeseries to be sorted")
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 2, 3},
+		Ys: []float64{4, 3, 2, 1},
+	}
+	if !ts.IsSorted() {
+		t.Fatalf("expected %v to be sorted", ts)
+	}
+
+	if !ts.Slice(0, 1).IsSorted() {
+		t.Fatalf("expected a single-item series to be sorted")
+	}
+
+	if ts.Reverse().IsSorted() {
+		t.Fatalf("expected %v not to be sorted", ts.Reverse())
+	}
+}
+
+func TestStableSort(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.StableSort()
+	})
+
+	// Many points share each X, with the Ys recording their input order
+	var ts Timeseries
+	for i := 0; i < 100; i++ {
+		ts.Append(float64((i*7)%5), float64(i))
+	}
+
+	ts.StableSort()
+	if !ts.IsSorted() {
+		t.Fatalf("expected StableSort() to sort %v", ts)
+	}
+
+	for i := 1; i < ts.Len(); i++ {
+		if ts.Xs[i] == ts.Xs[i-1] && ts.Ys[i] < ts.Ys[i-1] {
+			t.Fatalf("expected StableSort() to keep points sharing an X in order; instead got %v", ts)
+		}
+	}
+}
+
+func TestSortByY(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SortByY()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SortByYDesc()
+	})
+
+	nan := math.NaN()
+
+	// Each Y is ten times its X, so pairing can be checked point by point
+	ts := Timeseries{
+		Xs: []float64{1, 5, 0, 3, 2, 6},
+		Ys: []float64{10, 50, nan, 30, 20, 60},
+	}
+
+	check := func(name string, expected []float64) {
+		t.Helper()
+
+		for i, x := range ts.Xs {
+			if y := ts.Ys[i]; !(y == 10*x || (math.IsNaN(y) && x == 0)) {
+				t.Fatalf("expected %s() to keep Xs paired with their Ys; instead got %v", name, ts)
+			}
+
+			if x != expected[i] {
+				t.Fatalf("expected %s() to order the Xs as %v; instead got %v", name, expected, ts.Xs)
+			}
+		}
+	}
+
+	ts.SortByY()
+	check("SortByY", []float64{0, 1, 2, 3, 5, 6})
+
+	ts.SortByYDesc()
+	check("SortByYDesc", []float64{6, 5, 3, 2, 1, 0})
+}
+
+func TestLen(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Len()
+	})
+
+	if n := emptyTimeseries.Len(); n != 0 {
+		t.Fatalf("expected Len() = 0, instead got %v", n)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{2},
+	}
+	if n := ts.Len(); n != 1 {
+		t.Fatalf("expected Len() = 1, instead got %v", n)
+	}
+}
+
+func TestSum(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Sum()
+	})
+
+	if sum := emptyTimeseries.Sum(); sum != 0 {
+		t.Fatalf("expected Sum() of empty series = 0, instead got %v", sum)
+	}
+
+	ts1 := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{42},
+	}
+	if sum := ts1.Sum(); sum != 42 {
+		t.Fatalf("expected Sum() = 42, instead got %v", sum)
+	}
+
+	ts2 := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-10, 5, -2.5, 1},
+	}
+	if sum := ts2.Sum(); sum != -6.5 {
+		t.Fatalf("expected Sum() = -6.5, instead got %v", sum)
+	}
+}
+
+func TestMean(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Mean()
+	})
+
+	if mean := emptyTimeseries.Mean(); !math.IsNaN(mean) {
+		t.Fatalf("expected Mean() of empty series = NaN, instead got %v", mean)
+	}
+
+	ts1 := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{42},
+	}
+	if mean := ts1.Mean(); mean != 42 {
+		t.Fatalf("expected Mean() = 42, instead got %v", mean)
+	}
+
+	ts2 := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-10, 5, -2.5, 1},
+	}
+	if mean := ts2.Mean(); mean != -1.625 {
+		t.Fatalf("expected Mean() = -1.625, instead got %v", mean)
+	}
+}
+
+func TestSkipNaN(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SumSkipNaN()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.MeanSkipNaN()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.StdDevSkipNaN()
+	})
+
+	nan := math.NaN()
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7},
+		Ys: []float64{nan, 2, nan, 4, nan, 6, nan},
+	}
+
+	if sum := ts.Sum(); !math.IsNaN(sum) {
+		t.Fatalf("expected Sum() of %v to be NaN; instead got %v", ts, sum)
+	}
+	if sum := ts.SumSkipNaN(); sum != 12 {
+		t.Fatalf("expected SumSkipNaN() of %v to be 12; instead got %v", ts, sum)
+	}
+
+	if mean := ts.Mean(); !math.IsNaN(mean) {
+		t.Fatalf("expected Mean() of %v to be NaN; instead got %v", ts, mean)
+	}
+	if mean := ts.MeanSkipNaN(); mean != 4 {
+		t.Fatalf("expected MeanSkipNaN() of %v to be 4; instead got %v", ts, mean)
+	}
+
+	if sd := ts.StdDev(); !math.IsNaN(sd) {
+		t.Fatalf("expected StdDev() of %v to be NaN; instead got %v", ts, sd)
+	}
+	if sd := ts.StdDevSkipNaN(); math.Abs(sd-math.Sqrt(8.0/3)) > 1e-12 {
+		t.Fatalf("expected StdDevSkipNaN() of %v to be %v; instead got %v", ts, math.Sqrt(8.0/3), sd)
+	}
+
+	invalid := Timeseries{
+		Xs: []float64{1, 2},
+		Ys: []float64{nan, nan},
+	}
+	if sum := invalid.SumSkipNaN(); sum != 0 {
+		t.Fatalf("expected SumSkipNaN() of an all-NaN series to be 0; instead got %v", sum)
+	}
+	if mean := invalid.MeanSkipNaN(); !math.IsNaN(mean) {
+		t.Fatalf("expected MeanSkipNaN() of an all-NaN series to be NaN; instead got %v", mean)
+	}
+	if sd := invalid.StdDevSkipNaN(); !math.IsNaN(sd) {
+		t.Fatalf("expected StdDevSkipNaN() of an all-NaN series to be NaN; instead got %v", sd)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Describe()
+	})
+
+	s := emptyTimeseries.Describe()
+	if s.Count != 0 || !math.IsNaN(s.Mean) || !math.IsNaN(s.Min) || !math.IsNaN(s.Max) || !math.IsNaN(s.SampleStdDev) {
+		t.Fatalf("expected Describe() of empty series to be NaN but for its count; instead got %+v", s)
+	}
+
+	single := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{42},
+	}
+	s = single.Describe()
+	if s.Count != 1 || s.Mean != 42 || s.Min != 42 || s.Max != 42 || !math.IsNaN(s.SampleStdDev) {
+		t.Fatalf("expected Describe() of %v to have a NaN standard deviation only; instead got %+v", single, s)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{3, -1.5, 8, 2, 2, 10.25},
+	}
+	s = ts.Describe()
+
+	minY, _ := ts.MinY()
+	maxY, _ := ts.MaxY()
+	if s.Count != ts.Len() || s.Min != minY || s.Max != maxY {
+		t.Fatalf("expected Describe() of %v to count %v points between %v and %v; instead got %+v", ts, ts.Len(), minY, maxY, s)
+	}
+
+	if math.Abs(s.Mean-ts.Mean()) > 1e-12 || math.Abs(s.SampleStdDev-ts.SampleStdDev()) > 1e-12 {
+		t.Fatalf("expected Describe() of %v to have mean %v and standard deviation %v; instead got %+v", ts, ts.Mean(), ts.SampleStdDev(), s)
+	}
+
+	// The mean is exactly that of Mean(), not just close to it
+	thirds := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0.1, 0.2, 0.7},
+	}
+	if s := thirds.Describe(); s.Mean != thirds.Mean() {
+		t.Fatalf("expected Describe() of %v to have mean %v; instead got %v", thirds, thirds.Mean(), s.Mean)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.MinMax()
+	})
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Min() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.Max() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.MinMax() })
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{3, -1, 7, -1, 2, 7},
+	}
+
+	// Ties resolve to the first occurrence
+	if x, y := ts.Min(); x != 2 || y != -1 {
+		t.Fatalf("expected Min() = 2, -1; instead got %v, %v", x, y)
+	}
+
+	if x, y := ts.Max(); x != 3 || y != 7 {
+		t.Fatalf("expected Max() = 3, 7; instead got %v, %v", x, y)
+	}
+
+	if minX, minY, maxX, maxY := ts.MinMax(); minX != 2 || minY != -1 || maxX != 3 || maxY != 7 {
+		t.Fatalf("expected MinMax() = 2, -1, 3, 7; instead got %v, %v, %v, %v", minX, minY, maxX, maxY)
+	}
+
+	// NaNs never win a comparison, even in the first position
+	nan := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{math.NaN(), 3, math.NaN(), -3},
+	}
+	if x, y := nan.Min(); x != 4 || y != -3 {
+		t.Fatalf("expected Min() = 4, -3; instead got %v, %v", x, y)
+	}
+
+	if x, y := nan.Max(); x != 2 || y != 3 {
+		t.Fatalf("expected Max() = 2, 3; instead got %v, %v", x, y)
+	}
+
+	if minX, minY, maxX, maxY := nan.MinMax(); minX != 4 || minY != -3 || maxX != 2 || maxY != 3 {
+		t.Fatalf("expected MinMax() = 4, -3, 2, 3; instead got %v, %v, %v, %v", minX, minY, maxX, maxY)
+	}
+
+	allNaN := Timeseries{
+		Xs: []float64{1, 2},
+		Ys: []float64{math.NaN(), math.NaN()},
+	}
+	if x, y := allNaN.Max(); x != 1 || !math.IsNaN(y) {
+		t.Fatalf("expected Max() = 1, NaN; instead got %v, %v", x, y)
+	}
+
+	single := ts.Slice(0, 1)
+	if minX, minY, maxX, maxY := single.MinMax(); minX != 1 || minY != 3 || maxX != 1 || maxY != 3 {
+		t.Fatalf("expected MinMax() = 1, 3, 1, 3; instead got %v, %v, %v, %v", minX, minY, maxX, maxY)
+	}
+}
+
+func TestVariance(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Variance()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.StdDev()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SampleVariance()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.SampleStdDev()
+	})
+
+	if v := emptyTimeseries.Variance(); !math.IsNaN(v) {
+		t.Fatalf("expected Variance() of empty series = NaN, instead got %v", v)
+	}
+
+	if sd := emptyTimeseries.StdDev(); !math.IsNaN(sd) {
+		t.Fatalf("expected StdDev() of empty series = NaN, instead got %v", sd)
+	}
+
+	// The mean is 5 and the squared deviations sum to 32
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7, 8},
+		Ys: []float64{2, 4, 4, 4, 5, 5, 7, 9},
+	}
+
+	if v := ts.Variance(); v != 4 {
+		t.Fatalf("expected Variance() = 4, instead got %v", v)
+	}
+
+	if sd := ts.StdDev(); sd != 2 {
+		t.Fatalf("expected StdDev() = 2, instead got %v", sd)
+	}
+
+	if v := ts.SampleVariance(); math.Abs(v-32.0/7.0) > 1e-12 {
+		t.Fatalf("expected SampleVariance() = %v, instead got %v", 32.0/7.0, v)
+	}
+
+	expected := math.Sqrt(32.0 / 7.0)
+	if sd := ts.SampleStdDev(); math.Abs(sd-expected) > 1e-12 {
+		t.Fatalf("expected SampleStdDev() = %v, instead got %v", expected, sd)
+	}
+
+	if v := ts.Slice(0, 1).SampleVariance(); !math.IsNaN(v) {
+		t.Fatalf("expected SampleVariance() of a single item = NaN, instead got %v", v)
+	}
+
+	if sd := ts.Slice(0, 1).SampleStdDev(); !math.IsNaN(sd) {
+		t.Fatalf("expected SampleStdDev() of a single item = NaN, instead got %v", sd)
+	}
+}
+
+func TestMinYMaxY(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.MinY()
+	})
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.MinY() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.MaxY() })
+
+	single := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{42},
+	}
+	if y, i := single.MinY(); y != 42 || i != 0 {
+		t.Fatalf("expected MinY() = 42, 0; instead got %v, %v", y, i)
+	}
+	if y, i := single.MaxY(); y != 42 || i != 0 {
+		t.Fatalf("expected MaxY() = 42, 0; instead got %v, %v", y, i)
+	}
+
+	// Ties resolve to the first occurrence
+	ties := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{5, 1, 9, 1, 9},
+	}
+	if y, i := ties.MinY(); y != 1 || i != 1 {
+		t.Fatalf("expected MinY() = 1, 1; instead got %v, %v", y, i)
+	}
+	if y, i := ties.MaxY(); y != 9 || i != 2 {
+		t.Fatalf("expected MaxY() = 9, 2; instead got %v, %v", y, i)
+	}
+
+	// NaNs are skipped, even in the first position
+	nan := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{math.NaN(), 3, math.NaN(), -3},
+	}
+	if y, i := nan.MinY(); y != -3 || i != 3 {
+		t.Fatalf("expected MinY() = -3, 3; instead got %v, %v", y, i)
+	}
+	if y, i := nan.MaxY(); y != 3 || i != 1 {
+		t.Fatalf("expected MaxY() = 3, 1; instead got %v, %v", y, i)
+	}
+
+	allNaN := Timeseries{
+		Xs: []float64{1, 2},
+		Ys: []float64{math.NaN(), math.NaN()},
+	}
+	if y, i := allNaN.MinY(); !math.IsNaN(y) || i != 0 {
+		t.Fatalf("expected MinY() = NaN, 0; instead got %v, %v", y, i)
+	}
+}
+
+func TestArgMinArgMax(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.ArgMax()
+	})
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.ArgMin() })
+	assertPanic(t, "timeseries: empty timeseries", func() { emptyTimeseries.ArgMax() })
+
+	single := Timeseries{
+		Xs: []float64{1},
+		Ys: []float64{42},
+	}
+	if i := single.ArgMin(); i != 0 {
+		t.Fatalf("expected ArgMin() = 0; instead got %v", i)
+	}
+	if i := single.ArgMax(); i != 0 {
+		t.Fatalf("expected ArgMax() = 0; instead got %v", i)
+	}
+
+	// Ties resolve to the first occurrence, and NaNs are skipped
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6},
+		Ys: []float64{math.NaN(), 5, 1, 9, 1, 9},
+	}
+	if i := ts.ArgMin(); i != 2 {
+		t.Fatalf("expected ArgMin() = 2; instead got %v", i)
+	}
+	if i := ts.ArgMax(); i != 3 {
+		t.Fatalf("expected ArgMax() = 3; instead got %v", i)
+	}
+}
+
+func TestHampelFilter(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.HampelFilter(3, 3)
+	})
+
+	noisy := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		Ys: []float64{5, 6, 4, 5.5, 4.5, 6, 4, 5, 5.5},
+	}
+
+	assertPanic(t, "timeseries: invalid window size", func() { noisy.HampelFilter(0, 3) })
+	assertPanic(t, "timeseries: invalid window size", func() { noisy.HampelFilter(4, 3) })
+
+	if actual := noisy.HampelFilter(5, 3); !actual.Equal(noisy) {
+		t.Fatalf("expected HampelFilter(5, 3) to leave data without spikes unchanged; instead got %v", actual)
+	}
+
+	spiky := noisy.Clone()
+	spiky.Ys[4] = 1000
+	expected := noisy.Clone()
+	expected.Ys[4] = 5.5
+
+	if actual := spiky.HampelFilter(5, 3); !actual.Equal(expected) {
+		t.Fatalf("expected HampelFilter(5, 3) to return %v; instead got %v", expected, actual)
+	}
+
+	if spiky.Ys[4] != 1000 {
+		t.Fatalf("expected HampelFilter() to leave the series untouched; instead got %v", spiky)
+	}
+
+	// Edges are left unchanged
+	edge := noisy.Clone()
+	edge.Ys[0] = 1000
+	if actual := edge.HampelFilter(5, 3); !actual.Equal(edge) {
+		t.Fatalf("expected HampelFilter(5, 3) to leave the edges unchanged; instead got %v", actual)
+	}
+
+	// The spike shares a window with a NaN, and is left unchanged
+	spiky.Ys[6] = math.NaN()
+	if actual := spiky.HampelFilter(5, 3); actual.Ys[4] != 1000 || actual.Ys[0] != 5 {
+		t.Fatalf("expected HampelFilter(5, 3) to leave windows holding a NaN unchanged; instead got %v", actual)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Percentile(50)
+	})
+	assertPanic(t, "timeseries: percentile out of range", func() { emptyTimeseries.Percentile(-1) })
+	assertPanic(t, "timeseries: percentile out of range", func() { emptyTimeseries.Percentile(101) })
+	assertPanic(t, "timeseries: percentile out of range", func() { emptyTimeseries.Percentile(math.NaN()) })
+
+	if p := emptyTimeseries.Percentile(50); !math.IsNaN(p) {
+		t.Fatalf("expected Percentile(50) of empty series = NaN, instead got %v", p)
+	}
+
+	if m := emptyTimeseries.Median(); !math.IsNaN(m) {
+		t.Fatalf("expected Median() of empty series = NaN, instead got %v", m)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{40, 10, 30, 20},
+	}
+	original := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{40, 10, 30, 20},
+	}
+
+	cases := []struct {
+		p, expected float64
+	}{
+		{0, 10},
+		{100, 40},
+		{50, 25},
+		{25, 17.5},
+		{100.0 / 3, 20},
+	}
+	for _, c := range cases {
+		if actual := ts.Percentile(c.p); math.Abs(actual-c.expected) > 1e-12 {
+			t.Fatalf("expected Percentile(%v) = %v, instead got %v", c.p, c.expected, actual)
+		}
+	}
+
+	if m := ts.Median(); m != 25 {
+		t.Fatalf("expected Median() = 25, instead got %v", m)
+	}
+
+	if !ts.Equal(original) {
+		t.Fatalf("expected Percentile() to leave the series untouched; instead got %v", ts)
+	}
+
+	odd := ts.Slice(0, 3)
+	if m := odd.Median(); m != 30 {
+		t.Fatalf("expected Median() = 30, instead got %v", m)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Quantile(0.5)
+	})
+	assertPanic(t, "timeseries: quantile out of range", func() { emptyTimeseries.Quantile(-0.1) })
+	assertPanic(t, "timeseries: quantile out of range", func() { emptyTimeseries.Quantile(1.1) })
+
+	if q := emptyTimeseries.Quantile(0.5); !math.IsNaN(q) {
+		t.Fatalf("expected Quantile(0.5) of empty series = NaN, instead got %v", q)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{5, 1, 4, 2, 3},
+	}
+
+	cases := []struct {
+		p, expected float64
+	}{
+		{0, 1},
+		{0.5, 3},
+		{1, 5},
+		{0.9, 4.6},
+	}
+	for _, c := range cases {
+		if actual := ts.Quantile(c.p); math.Abs(actual-c.expected) > 1e-12 {
+			t.Fatalf("expected Quantile(%v) = %v, instead got %v", c.p, c.expected, actual)
+		}
+	}
+
+	if ts.Ys[0] != 5 || ts.Ys[4] != 3 {
+		t.Fatalf("expected Quantile() to leave the series untouched; instead got %v", ts)
+	}
+
+	// The median of an even number of Ys is the mean of the middle two
+	even := ts.Slice(0, 4)
+	if q := even.Quantile(0.5); q != 3 {
+		t.Fatalf("expected Quantile(0.5) of %v = 3, instead got %v", even, q)
+	}
+
+	withNaN := ts.Clone()
+	withNaN.Ys[2] = math.NaN()
+	for _, p := range []float64{0, 0.5, 1} {
+		if q := withNaN.Quantile(p); !math.IsNaN(q) {
+			t.Fatalf("expected Quantile(%v) of %v = NaN, instead got %v", p, withNaN, q)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Normalize()
+	})
+
+	if actual := emptyTimeseries.Normalize(); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Normalize() of empty series to return empty series; instead got %v", actual)
+	}
+
+	ramp := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{10, 20, 30, 40, 50},
+	}
+
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{0, 0.25, 0.5, 0.75, 1},
+	}
+	if actual := ramp.Normalize(); !actual.Equal(expected) {
+		t.Fatalf("expected ramp.Normalize() to return %v; instead got %v", expected, actual)
+	}
+
+	if ramp.Ys[0] != 10 {
+		t.Fatalf("expected Normalize() to leave the series untouched; instead got %v", ramp)
+	}
+
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{7, 7, 7},
+	}
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0, 0, 0},
+	}
+	if actual := constant.Normalize(); !actual.Equal(expected) {
+		t.Fatalf("expected constant.Normalize() to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestRescale(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Rescale(0, 1)
+	})
+	assertPanic(t, "timeseries: invalid range", func() { emptyTimeseries.Rescale(1, 1) })
+	assertPanic(t, "timeseries: invalid range", func() { emptyTimeseries.Rescale(1, 0) })
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-3.7, 12.1, 0.01, 5},
+	}
+
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-10, 10, 10*(0.01+3.7)/7.9 - 10, 10*(5+3.7)/7.9 - 10},
+	}
+	if actual := ts.Rescale(-10, 10); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected Rescale(-10, 10) to return %v; instead got %v", expected, actual)
+	}
+
+	// Rescaling into [0, 1] and back to the original range is the identity
+	_, min, _, max := ts.MinMax()
+	if actual := ts.Rescale(0, 1).Rescale(min, max); !actual.EqualApprox(ts, 1e-12) {
+		t.Fatalf("expected Rescale(0, 1).Rescale(%v, %v) to return %v; instead got %v", min, max, ts, actual)
+	}
+
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{7, 7, 7},
+	}
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-1, -1, -1},
+	}
+	if actual := constant.Rescale(-1, 1); !actual.Equal(expected) {
+		t.Fatalf("expected constant.Rescale(-1, 1) to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestStandardize(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Standardize()
+	})
+
+	if actual := emptyTimeseries.Standardize(); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Standardize() of empty series to return empty series; instead got %v", actual)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7, 8},
+		Ys: []float64{2, 4, 4, 4, 5, 5, 7, 9},
+	}
+
+	actual := ts.Standardize()
+	for i := range ts.Xs {
+		if actual.Xs[i] != ts.Xs[i] {
+			t.Fatalf("expected Standardize() to preserve Xs %v; instead got %v", ts.Xs, actual.Xs)
+		}
+	}
+
+	if mean := actual.Mean(); math.Abs(mean) > 1e-12 {
+		t.Fatalf("expected standardized mean = 0; instead got %v", mean)
+	}
+
+	if sd := actual.SampleStdDev(); math.Abs(sd-1) > 1e-12 {
+		t.Fatalf("expected standardized sample stddev = 1; instead got %v", sd)
+	}
+
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{7, 7, 7},
+	}
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{0, 0, 0},
+	}
+	if actual := constant.Standardize(); !actual.Equal(expected) {
+		t.Fatalf("expected constant.Standardize() to return %v; instead got %v", expected, actual)
+	}
+}
+
+func TestDestandardize(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Destandardize(0, 1)
+	})
+
+	for _, ts := range []Timeseries{
+		{
+			Xs: []float64{1, 2, 3, 4, 5},
+			Ys: []float64{0.3, -12.5, 7, 1e3, 42},
+		},
+		{
+			Xs: []float64{1, 2, 3},
+			Ys: []float64{7, 7, 7},
+		},
+	} {
+		actual := ts.Standardize().Destandardize(ts.Mean(), ts.SampleStdDev())
+		if !actual.EqualApprox(ts, 1e-9) {
+			t.Fatalf("expected Standardize().Destandardize() to return %v; instead got %v", ts, actual)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	square := func(x, y float64) float64 { return y * y }
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Map(square)
+	})
+
+	if actual := emptyTimeseries.Map(square); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Map() of empty series to return empty series; instead got %v", actual)
+	}
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-2, 0, 5},
+	}
+
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{4, 0, 25},
+	}
+	if actual := ts.Map(square); !actual.Equal(expected) {
+		t.Fatalf("expected Map(square) to return %v; instead got %v", expected, actual)
+	}
+
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-2, 0, 15},
+	}
+	actual := ts.Map(func(x, y float64) float64 { return x * y })
+	if !actual.Equal(expected) {
+		t.Fatalf("expected Map(x*y) to return %v; instead got %v", expected, actual)
+	}
+
+	actual.Xs[0], actual.Ys[0] = 1337, 1337
+	if ts.Xs[0] != 1 || ts.Ys[0] != -2 {
+		t.Fatalf("expected Map() to leave ts untouched; instead got %v", ts)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	all := func(x, y float64) bool { return true }
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Filter(all)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-1, 5, -3, 7},
+	}
+
+	if actual := ts.Filter(all); !actual.Equal(ts) {
+		t.Fatalf("expected Filter(all) to return %v; instead got %v", ts, actual)
+	}
+
+	none := func(x, y float64) bool { return false }
+	if actual := ts.Filter(none); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Filter(none) to return an empty series; instead got %v", actual)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{2, 4},
+		Ys: []float64{5, 7},
+	}
+	if actual := ts.Filter(func(x, y float64) bool { return y >= 0 }); !actual.Equal(expected) {
+		t.Fatalf("expected Filter(y >= 0) to return %v; instead got %v", expected, actual)
+	}
+
+	expected = Timeseries{
+		Xs: []float64{1, 3},
+		Ys: []float64{-1, -3},
+	}
+	actual := ts.Filter(func(x, y float64) bool { return int(x)%2 == 1 })
+	if !actual.Equal(expected) {
+		t.Fatalf("expected Filter(odd x) to return %v; instead got %v", expected, actual)
+	}
+
+	actual.Ys[0] = 1337
+	if ts.Ys[0] != -1 {
+		t.Fatalf("expected Filter() to leave ts untouched; instead got %v", ts)
+	}
+}
+
+func TestDropNaN(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.DropNaN()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.CountNaN()
+	})
+
+	nan, inf := math.NaN(), math.Inf(1)
+
+	invalid := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{nan, nan, nan},
+	}
+	if actual := invalid.DropNaN(); actual.Len() != 0 {
+		t.Fatalf("expected DropNaN() of an all-NaN series to be empty; instead got %v", actual)
+	}
+	if n := invalid.CountNaN(); n != 3 {
+		t.Fatalf("expected CountNaN() of an all-NaN series to be 3; instead got %d", n)
+	}
+
+	clean := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{4, 5, 6},
+	}
+	if actual := clean.DropNaN(); !actual.Equal(clean) {
+		t.Fatalf("expected DropNaN() of %v to return it unchanged; instead got %v", clean, actual)
+	}
+	if n := clean.CountNaN(); n != 0 {
+		t.Fatalf("expected CountNaN() of %v to be 0; instead got %d", clean, n)
+	}
+
+	mixed := Timeseries{
+		Xs: []float64{1, 2, 3, 4, nan, 6},
+		Ys: []float64{1, nan, 3, -inf, 5, 6},
+	}
+	expected := Timeseries{
+		Xs: []float64{1, 3, 6},
+		Ys: []float64{1, 3, 6},
+	}
+	if actual := mixed.DropNaN(); !actual.Equal(expected) {
+		t.Fatalf("expected DropNaN() of %v to return %v; instead got %v", mixed, expected, actual)
+	}
+	if n := mixed.CountNaN(); n != 3 {
+		t.Fatalf("expected CountNaN() of %v to be 3; instead got %d", mixed, n)
+	}
+	if mixed.Len() != 6 || !math.IsNaN(mixed.Ys[1]) {
+		t.Fatalf("expected DropNaN() to leave the receiver untouched; instead got %v", mixed)
+	}
+}
+
+func TestForwardFillBackFill(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.ForwardFill()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.BackFill()
+	})
+
+	nan := math.NaN()
+
+	invalid := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{nan, nan, nan},
+	}
+	if actual := invalid.ForwardFill(); actual.CountNaN() != 3 {
+		t.Fatalf("expected ForwardFill() of an all-NaN series to leave it unchanged; instead got %v", actual)
+	}
+	if actual := invalid.BackFill(); actual.CountNaN() != 3 {
+		t.Fatalf("expected BackFill() of an all-NaN series to leave it unchanged; instead got %v", actual)
+	}
+
+	holes := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5, 6, 7},
+		Ys: []float64{nan, 1, nan, nan, 4, nan, 6},
+	}
+
+	actual := holes.ForwardFill()
+	expected := Timeseries{
+		Xs: []float64{2, 3, 4, 5, 6, 7},
+		Ys: []float64{1, 1, 1, 4, 4, 6},
+	}
+	if !math.IsNaN(actual.Ys[0]) || !actual.Slice(1, 7).Equal(expected) {
+		t.Fatalf("expected ForwardFill() of %v to return NaN followed by %v; instead got %v", holes, expected, actual)
+	}
+
+	holes.Ys[6] = nan
+	actual = holes.BackFill()
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{1, 1, 4, 4, 4},
+	}
+	if !actual.Slice(0, 5).Equal(expected) || !math.IsNaN(actual.Ys[5]) || !math.IsNaN(actual.Ys[6]) {
+		t.Fatalf("expected BackFill() of %v to return %v followed by NaN; instead got %v", holes, expected, actual)
+	}
+
+	if holes.CountNaN() != 5 {
+		t.Fatalf("expected ForwardFill() and BackFill() to leave the receiver untouched; instead got %v", holes)
+	}
+}
+
+func TestScaleOffset(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Scale(2)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Offset(2)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-1.5, 0, 1024},
+	}
+
+	if actual := ts.Scale(1); !actual.Equal(ts) {
+		t.Fatalf("expected Scale(1) to be the identity of ts; instead got %v", actual)
+	}
+
+	if actual := ts.Offset(0); !actual.Equal(ts) {
+		t.Fatalf("expected Offset(0) to be the identity of ts; instead got %v", actual)
+	}
+
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-1.5 / 1024, 0, 1},
+	}
+	if actual := ts.Scale(1.0 / 1024); !actual.Equal(expected) {
+		t.Fatalf("expected Scale(1/1024) to return %v; instead got %v", expected, actual)
+	}
+
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{271.65, 273.15, 1297.15},
+	}
+	if actual := ts.Offset(273.15); !actual.Equal(expected) {
+		t.Fatalf("expected Offset(273.15) to return %v; instead got %v", expected, actual)
+	}
+
+	// Neither may alias the receiver
+	scaled, offset := ts.Scale(1), ts.Offset(0)
+	scaled.Xs[0], scaled.Ys[0] = 1337, 1337
+	offset.Xs[1], offset.Ys[1] = 1337, 1337
+	if ts.Xs[0] != 1 || ts.Ys[0] != -1.5 || ts.Xs[1] != 2 || ts.Ys[1] != 0 {
+		t.Fatalf("expected Scale() and Offset() to leave ts untouched; instead got %v", ts)
+	}
+}
+
+func TestClamp(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Clamp(0, 1)
+	})
+
+	assertPanic(t, "timeseries: invalid range", func() {
+		emptyTimeseries.Clamp(1, 0)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{-5, 0, 3, 12, math.NaN()},
+	}
+
+	actual := ts.Clamp(0, 10)
+	expected := []float64{0, 0, 3, 10}
+	for i, y := range expected {
+		if actual.Xs[i] != ts.Xs[i] || actual.Ys[i] != y {
+			t.Fatalf("expected Clamp(0, 10) to return %v at index %d; instead got %v", y, i, actual)
+		}
+	}
+
+	if !math.IsNaN(actual.Ys[4]) {
+		t.Fatalf("expected Clamp(0, 10) to pass NaN through; instead got %v", actual.Ys[4])
+	}
+
+	if ts.Ys[0] != -5 || ts.Ys[3] != 12 {
+		t.Fatalf("expected Clamp() to leave the receiver untouched; instead got %v", ts)
+	}
+
+	if actual := ts.Slice(0, 4).Clamp(3, 3); !actual.Equal(Timeseries{Xs: ts.Xs[:4], Ys: []float64{3, 3, 3, 3}}) {
+		t.Fatalf("expected Clamp(3, 3) to set every Y to 3; instead got %v", actual)
+	}
+}
+
+func TestAbsSquareSqrt(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Abs()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Square()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Sqrt()
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{-4, 0, 2.25, 9},
+	}
+
+	expected := Timeseries{
+		Xs: ts.Xs,
+		Ys: []float64{4, 0, 2.25, 9},
+	}
+	if actual := ts.Abs(); !actual.Equal(expected) {
+		t.Fatalf("expected Abs() to return %v; instead got %v", expected, actual)
+	}
+
+	expected.Ys = []float64{16, 0, 5.0625, 81}
+	if actual := ts.Square(); !actual.Equal(expected) {
+		t.Fatalf("expected Square() to return %v; instead got %v", expected, actual)
+	}
+
+	actual := ts.Sqrt()
+	if !math.IsNaN(actual.Ys[0]) {
+		t.Fatalf("expected Sqrt() of a negative Y to be NaN; instead got %v", actual.Ys[0])
+	}
+
+	expected.Ys = []float64{0, 1.5, 3}
+	if rest := actual.Slice(1, 4); !rest.Equal(Timeseries{Xs: ts.Xs[1:], Ys: expected.Ys}) {
+		t.Fatalf("expected Sqrt() to return %v at indices 1 to 3; instead got %v", expected.Ys, rest)
 	}
-	if actual := ts1.MovingAverage(4); !actual.Equal(expectedForMA4) {
-		t.Fatalf("expected MovingAverage(4) to return %v; instead got %v", expectedForMA4, actual)
+
+	if ts.Ys[0] != -4 {
+		t.Fatalf("expected the transforms to leave the receiver untouched; instead got %v", ts)
+	}
+}
+
+func TestLogExp(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Log()
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Exp()
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{0.5, 1, math.E, 1234.5},
+	}
+
+	expected := Timeseries{
+		Xs: ts.Xs,
+		Ys: []float64{-math.Ln2, 0, 1, math.Log(1234.5)},
+	}
+	if actual := ts.Log(); !actual.EqualApprox(expected, 1e-12) {
+		t.Fatalf("expected Log() to return %v; instead got %v", expected, actual)
 	}
 
+	if actual := ts.Log().Exp(); !actual.EqualApprox(ts, 1e-9) {
+		t.Fatalf("expected Log().Exp() to return %v; instead got %v", ts, actual)
+	}
+
+	nonPositive := Timeseries{
+		Xs: []float64{1, 2},
+		Ys: []float64{0, -1},
+	}
+	if actual := nonPositive.Log(); !math.IsInf(actual.Ys[0], -1) || !math.IsNaN(actual.Ys[1]) {
+		t.Fatalf("expected Log() of %v to return -Inf and NaN; instead got %v", nonPositive, actual)
+	}
 }
 
-func TestLen(t *testing.T) {
+func TestScaleOffsetInPlace(t *testing.T) {
 	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
-		mismatchedTimeseries.Len()
+		mismatchedTimeseries.ScaleInPlace(2)
 	})
 
-	if n := emptyTimeseries.Len(); n != 0 {
-		t.Fatalf("expected Len() = 0, instead got %v", n)
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.OffsetInPlace(2)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-1.5, 0, 1024},
+	}
+
+	ts.ScaleInPlace(2)
+	expected := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-3, 0, 2048},
+	}
+	if !ts.Equal(expected) {
+		t.Fatalf("expected %v after ScaleInPlace(2); instead got %v", expected, ts)
+	}
+
+	ts.OffsetInPlace(-1)
+	expected = Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{-4, -1, 2047},
+	}
+	if !ts.Equal(expected) {
+		t.Fatalf("expected %v after OffsetInPlace(-1); instead got %v", expected, ts)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Downsample(1, Mean)
+	})
+
+	assertPanic(t, "timeseries: invalid bucket width", func() {
+		emptyTimeseries.Downsample(0, Mean)
+	})
+
+	if actual := emptyTimeseries.Downsample(10, Mean); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected Downsample() of empty series to return empty series; instead got %v", actual)
 	}
 
+	// The bucket [20, 30) is empty and must be skipped
 	ts := Timeseries{
-		Xs: []float64{1},
-		Ys: []float64{2},
+		Xs: []float64{-5, 0, 3, 9, 10, 15, 31, 39},
+		Ys: []float64{7, 1, 2, 6, 5, 3, 4, 8},
 	}
-	if n := ts.Len(); n != 1 {
-		t.Fatalf("expected Len() = 1, instead got %v", n)
+
+	cases := []struct {
+		name     string
+		agg      func([]float64) float64
+		expected []float64
+	}{
+		{"Mean", Mean, []float64{7, 3, 4, 6}},
+		{"Min", Min, []float64{7, 1, 3, 4}},
+		{"Max", Max, []float64{7, 6, 5, 8}},
+		{"Last", Last, []float64{7, 6, 3, 8}},
+	}
+	for _, c := range cases {
+		expected := Timeseries{
+			Xs: []float64{-10, 0, 10, 30},
+			Ys: c.expected,
+		}
+		if actual := ts.Downsample(10, c.agg); !actual.Equal(expected) {
+			t.Fatalf("expected Downsample(10, %s) to return %v; instead got %v", c.name, expected, actual)
+		}
+	}
+}
+
+func TestDedupeX(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.DedupeX(nil)
+	})
+
+	if actual := emptyTimeseries.DedupeX(nil); !actual.Equal(emptyTimeseries) {
+		t.Fatalf("expected DedupeX() of empty series to return empty series; instead got %v", actual)
+	}
+
+	unique := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{4, 5, 6},
+	}
+	if actual := unique.DedupeX(nil); !actual.Equal(unique) {
+		t.Fatalf("expected DedupeX() of %v to return it unchanged; instead got %v", unique, actual)
+	}
+
+	same := Timeseries{
+		Xs: []float64{7, 7, 7},
+		Ys: []float64{1, 2, 6},
+	}
+	expected := Timeseries{
+		Xs: []float64{7},
+		Ys: []float64{3},
+	}
+	if actual := same.DedupeX(nil); !actual.Equal(expected) {
+		t.Fatalf("expected DedupeX(nil) of %v to return %v; instead got %v", same, expected, actual)
+	}
+
+	mixed := Timeseries{
+		Xs: []float64{1, 1, 2, 3, 3, 3, 4},
+		Ys: []float64{2, 4, 5, 1, 9, 2, 8},
+	}
+	cases := []struct {
+		name     string
+		agg      func([]float64) float64
+		expected []float64
+	}{
+		{"nil", nil, []float64{3, 5, 4, 8}},
+		{"Max", Max, []float64{4, 5, 9, 8}},
+		{"Last", Last, []float64{4, 5, 2, 8}},
+	}
+	for _, c := range cases {
+		expected := Timeseries{
+			Xs: []float64{1, 2, 3, 4},
+			Ys: c.expected,
+		}
+		actual := mixed.DedupeX(c.agg)
+		if !actual.Equal(expected) {
+			t.Fatalf("expected DedupeX(%s) to return %v; instead got %v", c.name, expected, actual)
+		}
+
+		for i := 1; i < actual.Len(); i++ {
+			if !(actual.Xs[i] > actual.Xs[i-1]) {
+				t.Fatalf("expected DedupeX(%s) to return strictly increasing Xs; instead got %v", c.name, actual.Xs)
+			}
+		}
+	}
+}
+
+func TestLTTB(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.LTTB(3)
+	})
+
+	var sine Timeseries
+	for i := 0; i < 1000; i++ {
+		x := float64(i) / 10
+		sine.Append(x, math.Sin(x))
+	}
+
+	for _, threshold := range []int{-1, 2, 1000, 1001} {
+		if actual := sine.LTTB(threshold); !actual.Equal(sine) {
+			t.Fatalf("expected LTTB(%v) to return the series unchanged", threshold)
+		}
+	}
+
+	for _, threshold := range []int{3, 100, 999} {
+		actual := sine.LTTB(threshold)
+		if n := actual.Len(); n != threshold {
+			t.Fatalf("expected LTTB(%v) to return %v points; instead got %v", threshold, threshold, n)
+		}
+
+		if x, y := actual.First(); x != sine.Xs[0] || y != sine.Ys[0] {
+			t.Fatalf("expected LTTB(%v) to retain the first point; instead got %v, %v", threshold, x, y)
+		}
+
+		if x, y := actual.Last(); x != sine.Xs[999] || y != sine.Ys[999] {
+			t.Fatalf("expected LTTB(%v) to retain the last point; instead got %v, %v", threshold, x, y)
+		}
+
+		if !sort.Float64sAreSorted(actual.Xs) {
+			t.Fatalf("expected LTTB(%v) to preserve the order of the points", threshold)
+		}
+	}
+
+	// Peaks and troughs survive downsampling
+	_, maxY := sine.LTTB(100).Max()
+	if maxY < 0.99 {
+		t.Fatalf("expected LTTB(100) to retain a peak near 1; instead got max %v", maxY)
+	}
+
+	// The last bucket reaches the point before the last one
+	var spike Timeseries
+	for i := 0; i < 17; i++ {
+		spike.Append(float64(i), 0)
+	}
+	spike.Ys[15] = 1000
+
+	if actual := spike.LTTB(13); actual.Xs[11] != 15 || actual.Ys[11] != 1000 {
+		t.Fatalf("expected LTTB(13) to retain the spike at 15; instead got %v", actual)
+	}
+}
+
+func TestAggregations(t *testing.T) {
+	nan := math.NaN()
+	for _, agg := range []func([]float64) float64{Mean, Min, Max, Last} {
+		if y := agg(nil); !math.IsNaN(y) {
+			t.Fatalf("expected aggregating an empty slice to return NaN; instead got %v", y)
+		}
+	}
+
+	if y := Min([]float64{nan, 3, nan, -2}); y != -2 {
+		t.Fatalf("expected Min() = -2; instead got %v", y)
+	}
+
+	if y := Max([]float64{nan, 3, nan, -2}); y != 3 {
+		t.Fatalf("expected Max() = 3; instead got %v", y)
+	}
+
+	if y := Max([]float64{nan, nan}); !math.IsNaN(y) {
+		t.Fatalf("expected Max() of only NaNs = NaN; instead got %v", y)
+	}
+}
+
+func BenchmarkDownsample(b *testing.B) {
+	var ts Timeseries
+	for i := 0; i < 1000000; i++ {
+		ts.Append(float64(i), math.Sin(float64(i)/1000))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ts.Downsample(500, Mean)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Add(emptyTimeseries)
+	})
+
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		emptyTimeseries.Sub(mismatchedTimeseries)
+	})
+
+	failures := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{1, 0, 3, -2},
+	}
+	requests := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{4, 0, 0, 0},
+	}
+
+	assertPanic(t, "timeseries: Xs of the series differ", func() { failures.Mul(requests.Slice(0, 3)) })
+	assertPanic(t, "timeseries: Xs of the series differ", func() {
+		failures.Div(Timeseries{Xs: []float64{1, 2, 3, 5}, Ys: requests.Ys})
+	})
+
+	cases := []struct {
+		name     string
+		actual   Timeseries
+		expected []float64
+	}{
+		{"Add", failures.Add(requests), []float64{5, 0, 3, -2}},
+		{"Sub", failures.Sub(requests), []float64{-3, 0, 3, -2}},
+		{"Mul", failures.Mul(requests), []float64{4, 0, 0, 0}},
+	}
+	for _, c := range cases {
+		if expected := (Timeseries{Xs: failures.Xs, Ys: c.expected}); !c.actual.Equal(expected) {
+			t.Fatalf("expected %s to return %v; instead got %v", c.name, expected, c.actual)
+		}
+	}
+
+	// Division by zero follows IEEE 754
+	rate := failures.Div(requests)
+	if x, y := rate.At(0); x != 1 || y != 0.25 {
+		t.Fatalf("expected Div() to return 1, 0.25 at index 0; instead got %v, %v", x, y)
+	}
+
+	if y := rate.Ys[1]; !math.IsNaN(y) {
+		t.Fatalf("expected 0/0 = NaN; instead got %v", y)
+	}
+
+	if y := rate.Ys[2]; !math.IsInf(y, 1) {
+		t.Fatalf("expected 3/0 = +Inf; instead got %v", y)
+	}
+
+	if y := rate.Ys[3]; !math.IsInf(y, -1) {
+		t.Fatalf("expected -2/0 = -Inf; instead got %v", y)
+	}
+}
+
+func TestAutoCorrelation(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.AutoCorrelation(0)
+	})
+	assertPanic(t, "timeseries: invalid lag", func() { emptyTimeseries.AutoCorrelation(0) })
+
+	const period = 20
+	var sine Timeseries
+	for i := 0; i < 10*period; i++ {
+		sine.Append(float64(i), math.Sin(2*math.Pi*float64(i)/period))
+	}
+
+	assertPanic(t, "timeseries: invalid lag", func() { sine.AutoCorrelation(-1) })
+	assertPanic(t, "timeseries: invalid lag", func() { sine.AutoCorrelation(sine.Len()) })
+
+	acf := sine.AutoCorrelation(2 * period)
+	if n := acf.Len(); n != 2*period+1 {
+		t.Fatalf("expected AutoCorrelation(%v) to return %v lags; instead got %v", 2*period, 2*period+1, n)
+	}
+
+	if x, y := acf.First(); x != 0 || math.Abs(y-1) > 1e-12 {
+		t.Fatalf("expected the autocorrelation at lag 0 to be 1; instead got %v at lag %v", y, x)
+	}
+
+	// Past the first trough, the autocorrelation peaks at the period
+	if _, i := acf.After(period / 2).MaxY(); acf.After(period / 2).Xs[i] != period {
+		t.Fatalf("expected the autocorrelation to peak at lag %v; instead got %v", period, acf)
+	}
+
+	if _, y := acf.At(period / 2); y > -0.9 {
+		t.Fatalf("expected a strong negative autocorrelation at half the period; instead got %v", y)
+	}
+}
+
+func TestCrossCorrelation(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.CrossCorrelation(emptyTimeseries, 0)
+	})
+
+	// An aperiodic signal, where other is the receiver delayed by 3 points
+	const n, shift = 100, 3
+	var base Timeseries
+	for i := 0; i < n+shift; i++ {
+		base.Append(float64(i), math.Sin(float64(i*i)/7))
+	}
+
+	ts := base.Slice(shift, n+shift)
+	other := base.Slice(0, n)
+
+	assertPanic(t, "timeseries: series lengths differ", func() { ts.CrossCorrelation(other.Slice(1, n), 1) })
+	assertPanic(t, "timeseries: invalid lag", func() { ts.CrossCorrelation(other, -1) })
+	assertPanic(t, "timeseries: invalid lag", func() { ts.CrossCorrelation(other, n) })
+
+	ccf := ts.CrossCorrelation(other, 10)
+	if m := ccf.Len(); m != 21 {
+		t.Fatalf("expected CrossCorrelation(other, 10) to return 21 lags; instead got %v", m)
+	}
+
+	if x, _ := ccf.First(); x != -10 {
+		t.Fatalf("expected CrossCorrelation(other, 10) to start at lag -10; instead got %v", x)
+	}
+
+	if x, _ := ccf.At(10 + shift); x != shift {
+		t.Fatalf("expected CrossCorrelation(other, 10) to hold lag %v at index %v; instead got %v", shift, 10+shift, x)
+	}
+
+	if lag, _ := ccf.Max(); lag != shift {
+		t.Fatalf("expected the cross-correlation to peak at lag %v; instead got %v", shift, lag)
+	}
+
+	// Swapping the series mirrors the lag
+	if lag, _ := other.CrossCorrelation(ts, 10).Max(); lag != -shift {
+		t.Fatalf("expected the cross-correlation to peak at lag %v; instead got %v", -shift, lag)
+	}
+
+	// Against itself, the cross-correlation at lag 0 is 1
+	if _, y := ts.CrossCorrelation(ts, 0).First(); math.Abs(y-1) > 1e-12 {
+		t.Fatalf("expected the cross-correlation of ts with itself to be 1 at lag 0; instead got %v", y)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Correlation(emptyTimeseries)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{2, -1, 4, 8, 3},
+	}
+
+	assertPanic(t, "timeseries: series lengths differ", func() { ts.Correlation(ts.Slice(0, 4)) })
+
+	if r := ts.Correlation(ts.Scale(3).Offset(-7)); math.Abs(r-1) > 1e-12 {
+		t.Fatalf("expected the correlation with a positive affine transform to be 1; instead got %v", r)
+	}
+
+	if r := ts.Correlation(ts.Scale(-0.5)); math.Abs(r+1) > 1e-12 {
+		t.Fatalf("expected the correlation with a negative affine transform to be -1; instead got %v", r)
+	}
+
+	// The deviations of these Ys from their mean are orthogonal
+	linear := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{1, 2, 3, 4, 5},
+	}
+	symmetric := Timeseries{
+		Xs: []float64{1, 2, 3, 4, 5},
+		Ys: []float64{2, 1, 0, 1, 2},
+	}
+	if r := linear.Correlation(symmetric); math.Abs(r) > 1e-12 {
+		t.Fatalf("expected the correlation of uncorrelated series to be 0; instead got %v", r)
+	}
+
+	constant := ts.Map(func(x, y float64) float64 { return 7 })
+	if r := ts.Correlation(constant); !math.IsNaN(r) {
+		t.Fatalf("expected the correlation with a constant series to be NaN; instead got %v", r)
+	}
+}
+
+func TestCovariance(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Covariance(emptyTimeseries)
+	})
+
+	ts := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{1, 2, 3, 4},
+	}
+	other := Timeseries{
+		Xs: []float64{1, 2, 3, 4},
+		Ys: []float64{2, 4, 6, 9},
+	}
+
+	assertPanic(t, "timeseries: series lengths differ", func() { ts.Covariance(other.Slice(0, 3)) })
+
+	// The deviations from the means are [-1.5 -0.5 0.5 1.5] and
+	// [-3.25 -1.25 0.75 3.75], whose products sum to 11.5
+	if c := ts.Covariance(other); math.Abs(c-11.5/3) > 1e-12 {
+		t.Fatalf("expected ts.Covariance(other) to be %v; instead got %v", 11.5/3, c)
+	}
+
+	if c := ts.Covariance(ts); math.Abs(c-ts.SampleVariance()) > 1e-12 {
+		t.Fatalf("expected the covariance of ts with itself to be its sample variance %v; instead got %v", ts.SampleVariance(), c)
+	}
+
+	constant := ts.Map(func(x, y float64) float64 { return 7 })
+	if c := ts.Covariance(constant); c != 0 {
+		t.Fatalf("expected the covariance with a constant series to be 0; instead got %v", c)
+	}
+
+	if c := ts.Slice(0, 1).Covariance(other.Slice(0, 1)); !math.IsNaN(c) {
+		t.Fatalf("expected the covariance of single-item series to be NaN; instead got %v", c)
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.Outliers(3)
+	})
+
+	if outliers := emptyTimeseries.Outliers(3); outliers == nil || len(outliers) != 0 {
+		t.Fatalf("expected no outliers in an empty series; instead got %#v", outliers)
+	}
+
+	var spiky, expected Timeseries
+	for i := 0; i < 20; i++ {
+		y := 10 + float64(i%3)
+		if i == 13 {
+			y = 100
+		} else {
+			expected.Append(float64(i), y)
+		}
+
+		spiky.Append(float64(i), y)
+	}
+
+	if outliers := spiky.Outliers(3); len(outliers) != 1 || outliers[0] != 13 {
+		t.Fatalf("expected spiky.Outliers(3) = [13]; instead got %v", outliers)
+	}
+
+	if actual := spiky.RemoveOutliers(3); !actual.Equal(expected) {
+		t.Fatalf("expected spiky.RemoveOutliers(3) to return %v; instead got %v", expected, actual)
+	}
+
+	constant := Timeseries{
+		Xs: []float64{1, 2, 3},
+		Ys: []float64{7, 7, 7},
+	}
+	if outliers := constant.Outliers(0); outliers == nil || len(outliers) != 0 {
+		t.Fatalf("expected no outliers in a constant series; instead got %#v", outliers)
+	}
+
+	if actual := constant.RemoveOutliers(0); !actual.Equal(constant) {
+		t.Fatalf("expected constant.RemoveOutliers(0) to return %v; instead got %v", constant, actual)
+	}
+}
+
+func TestChangePoints(t *testing.T) {
+	assertPanic(t, "timeseries: Xs and Ys slice length mismatch", func() {
+		mismatchedTimeseries.ChangePoints(1, 0)
+	})
+
+	assertPanic(t, "timeseries: threshold out of range", func() {
+		emptyTimeseries.ChangePoints(0, 0)
+	})
+
+	assertPanic(t, "timeseries: negative drift", func() {
+		emptyTimeseries.ChangePoints(1, -1)
+	})
+
+	if changes := emptyTimeseries.ChangePoints(1, 0); changes == nil || len(changes) != 0 {
+		t.Fatalf("expected no change points in an empty series; instead got %#v", changes)
+	}
+
+	// Noisy series stepping from a mean of 0 to a mean of 10 at index 20,
+	// and back down to 0 at index 30
+	var ts Timeseries
+	for i := 0; i < 40; i++ {
+		y := float64(i%2) - 0.5
+		if i >= 20 && i < 30 {
+			y += 10
+		}
+		ts.Append(float64(i), y)
+	}
+
+	changes := ts.ChangePoints(5, 1)
+	if len(changes) != 2 || changes[0] != 20 || changes[1] != 30 {
+		t.Fatalf("expected ts.ChangePoints(5, 1) = [20 30]; instead got %v", changes)
+	}
+
+	if changes := ts.ChangePoints(50, 1); len(changes) != 0 {
+		t.Fatalf("expected no change points with a high threshold; instead got %v", changes)
 	}
 }
 